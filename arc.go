@@ -8,6 +8,7 @@
 // and frequently-used cache when misses hit a ghost cache of recently evicted entries. However, this version
 // permits the deletion of entries and the precise details of pivoting rate and ghost cache eviction are different.
 // Given the same sequence of mutually-supported operations, the contents of the two implementations may diverge.
+// Classic implements the algorithm exactly as described in the paper, and CAR implements its clock-based variant.
 //
 // I am not a lawyer. This is not legal advice.
 //
@@ -17,171 +18,404 @@
 // 	https://www.google.com/patents/US6996676
 package arc
 
-import "bursavich.dev/arc/internal/list"
-
-type empty struct{}
-
-type item[K comparable, V any] struct {
-	key K
-	val V
-	hot bool
-}
-
-type subCache[K comparable, V any] struct {
-	tbl map[K]*list.Element[item[K, V]]
-	mru list.List[item[K, V]]
-	mfu list.List[item[K, V]]
-}
-
-func (c *subCache[K, V]) init(size int) {
-	c.tbl = make(map[K]*list.Element[item[K, V]], size)
-	c.mru.Init()
-	c.mfu.Init()
-}
-
-func (c *subCache[K, V]) remove(e *list.Element[item[K, V]]) item[K, V] {
-	e.List().Remove(e)
-	delete(c.tbl, e.Value.key)
-	return e.Value
-}
+import (
+	"math"
+	"math/bits"
+	"time"
+)
 
 // Cache is an adaptive replacement cache.
 // It is not safe for concurrent access.
 type Cache[K comparable, V any] struct {
-	max   int // max live size
-	pivot int // pivot
-	live  subCache[K, V]
-	dead  subCache[K, empty]
+	max     int64         // max live weight
+	pivot   int64         // pivot
+	ttl     time.Duration // default time-to-live
+	clock   Clock
+	weigher Weigher[K, V]
+	onEvict EvictFunc[K, V]
+	ghost   GhostHooks[K]
+	items   store[K, V] // live and dead items
+	stats   stats
 }
 
-// New creates a new Cache.
-func New[K comparable, V any](size int) *Cache[K, V] {
+// New creates a new Cache that holds up to size live items,
+// or up to size total weight if it has a Weigher.
+func New[K comparable, V any](size int, opts ...Option) *Cache[K, V] {
+	return newCache[K, V](size, size, opts)
+}
+
+// newCache creates a new Cache with room preallocated for up to hint live items
+// and hint dead items. The hint is ignored if the cache has a Weigher.
+func newCache[K comparable, V any](size, hint int, opts []Option) *Cache[K, V] {
 	if size <= 0 {
 		panic("arc: size must be greater than 0")
 	}
+	o := newOptions(opts)
 	c := &Cache[K, V]{
-		max:   size,
-		pivot: size / 2,
+		max:   int64(size),
+		pivot: int64(size) / 2,
+		ttl:   o.ttl,
+		clock: o.clock,
+	}
+	if o.weigher != nil {
+		fn, ok := o.weigher.(Weigher[K, V])
+		if !ok {
+			panic("arc: Weigher type does not match cache")
+		}
+		c.weigher = fn
+		hint = 0 // size is not a count, so don't use it as a hint
+	}
+	if o.evictFunc != nil {
+		fn, ok := o.evictFunc.(EvictFunc[K, V])
+		if !ok {
+			panic("arc: EvictFunc type does not match cache")
+		}
+		c.onEvict = fn
 	}
-	c.live.init(size)
-	c.dead.init(size)
+	if o.ghostHooks != nil {
+		hooks, ok := o.ghostHooks.(GhostHooks[K])
+		if !ok {
+			panic("arc: GhostHooks type does not match cache")
+		}
+		c.ghost = hooks
+	}
+	c.items.init(hint)
+	c.gauge()
 	return c
 }
 
 // Len returns the number of live items in the cache.
+// It includes expired items that have not yet been removed.
 func (c *Cache[K, V]) Len() int {
-	return len(c.live.tbl)
+	return c.items.liveLen()
 }
 
 // Get reads the key's value from the cache.
 func (c *Cache[K, V]) Get(key K) (value V, found bool) {
-	if e, ok := c.get(key); ok {
-		return e.Value.val, true
+	if i, seg, ok := c.get(key); ok {
+		c.stats.hits[seg].Add(1)
+		return c.items.slots[i].val, true
 	}
+	c.stats.misses.Add(1)
 	var zero V
 	return zero, false
 }
 
+// Clear removes all live items from the cache. The dead cache and pivot are retained,
+// so the cache warms back up with the shape it had adapted to.
+func (c *Cache[K, V]) Clear() {
+	defer c.gauge()
+	c.clearLive()
+}
+
+// Purge removes all live and dead items from the cache and resets the pivot,
+// returning the cache to the state produced by New. Stats counters are retained.
+func (c *Cache[K, V]) Purge() {
+	defer c.gauge()
+	c.clearLive()
+	for _, l := range []int32{deadMRU, deadMFU} {
+		for i := c.items.front(l); i != l; i = c.items.slots[i].next {
+			c.ghostDropped(c.items.slots[i])
+		}
+	}
+	c.items.reset()
+	c.pivot = c.max / 2
+}
+
+func (c *Cache[K, V]) clearLive() {
+	for _, l := range []int32{liveMRU, liveMFU} {
+		for i := c.items.front(l); i != l; {
+			next := c.items.slots[i].next
+			it := c.items.remove(i)
+			c.evicted(it.key, it.val, EvictCleared)
+			i = next
+		}
+	}
+}
+
+// Resize changes the max number of live items, or max total weight, in the cache
+// and scales the pivot proportionally. If the cache shrinks, items are evicted
+// until neither its live items nor its dead items exceed the new size.
+func (c *Cache[K, V]) Resize(size int) {
+	if size <= 0 {
+		panic("arc: size must be greater than 0")
+	}
+	defer c.gauge()
+	c.pivot = scale(c.pivot, int64(size), c.max)
+	c.max = int64(size)
+	for c.items.liveWeight() > c.max {
+		c.evictLive(false)
+	}
+	for c.items.deadWeight() > c.max {
+		c.evictDead()
+	}
+}
+
+// Peek reads the key's value from the cache without updating its recency or frequency.
+func (c *Cache[K, V]) Peek(key K) (value V, found bool) {
+	if i, ok := c.live(key); ok && !c.expired(c.items.slots[i].exp) {
+		return c.items.slots[i].val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains reports whether the key's value is in the cache
+// without updating its recency or frequency.
+func (c *Cache[K, V]) Contains(key K) bool {
+	i, ok := c.live(key)
+	return ok && !c.expired(c.items.slots[i].exp)
+}
+
+// WasRecentlyEvicted reports whether the key's value was recently evicted
+// and the key remains in the dead cache, where setting it again would adapt the pivot.
+func (c *Cache[K, V]) WasRecentlyEvicted(key K) bool {
+	_, ok := c.dead(key)
+	return ok
+}
+
 // Set writes the key's value to the cache.
+// It expires after the cache's default time-to-live, if any.
 func (c *Cache[K, V]) Set(key K, value V) {
-	if e, ok := c.get(key); ok {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL writes the key's value to the cache.
+// It expires after the given time-to-live, or never if ttl is not positive.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	defer c.gauge()
+	var exp int64
+	if ttl > 0 {
+		now := c.now()
+		if exp = now + int64(ttl); exp < now {
+			exp = math.MaxInt64 // overflow
+		}
+	}
+	wt := c.weigh(key, value)
+	if wt > c.max {
+		// Too heavy to cache.
+		if i, ok := c.live(key); ok {
+			it := c.items.remove(i)
+			c.evicted(key, it.val, EvictReplaced)
+		} else if i, ok := c.dead(key); ok {
+			c.ghostDropped(c.items.remove(i))
+		}
+		c.evicted(key, value, EvictRejected)
+		return
+	}
+	if i, _, ok := c.get(key); ok {
 		// Live cache hit.
-		e.Value.val = value
+		p := &c.items.slots[i]
+		old := p.val
+		if wt == p.wt {
+			p.val = value
+			p.exp = exp
+		} else {
+			// Remove the item before making room for its new weight so it isn't evicted itself.
+			c.items.remove(i)
+			c.evict(true, wt)
+			c.items.push(liveMFU, key, value, exp, wt)
+		}
+		c.evicted(key, old, EvictReplaced)
 		return
 	}
-	if e, ok := c.dead.tbl[key]; ok {
+	if i, ok := c.dead(key); ok {
 		// Dead cache hit.
-		if e.Value.hot {
-			c.pivot = max(0, c.pivot-1)
+		it := c.items.remove(i)
+		seg := it.seg()
+		pivot := c.pivot
+		if seg == MFU {
+			c.pivot = max(0, c.pivot-it.wt)
 		} else {
-			c.pivot = min(c.max, c.pivot+1)
+			c.pivot = min(c.max, c.pivot+it.wt)
+		}
+		c.stats.ghostHits[seg].Add(1)
+		if c.ghost.Hit != nil {
+			c.ghost.Hit(key, seg, pivot, c.pivot)
 		}
-		c.dead.remove(e)
-		c.evict(e.Value.hot)
-		c.live.tbl[key] = c.live.mfu.PushFront(item[K, V]{
-			key: key,
-			val: value,
-			hot: true,
-		})
+		c.evict(seg == MFU, wt)
+		c.items.push(liveMFU, key, value, exp, wt)
 		return
 	}
 	// Cache miss.
-	c.evict(false)
-	c.live.tbl[key] = c.live.mru.PushFront(item[K, V]{
-		key: key,
-		val: value,
-		hot: false,
-	})
+	c.evict(false, wt)
+	c.items.push(liveMRU, key, value, exp, wt)
 }
 
 // Delete deletes the key's value from the cache.
 func (c *Cache[K, V]) Delete(key K) {
-	if e, ok := c.live.tbl[key]; ok {
+	defer c.gauge()
+	if i, ok := c.live(key); ok {
 		// Live cache hit.
-		c.live.remove(e)
-	} else if e, ok := c.dead.tbl[key]; ok {
+		it := c.items.remove(i)
+		c.evicted(it.key, it.val, EvictDeleted)
+	} else if i, ok := c.dead(key); ok {
 		// Dead cache hit.
-		c.dead.remove(e)
+		c.ghostDropped(c.items.remove(i))
 	}
 }
 
-func (c *Cache[K, V]) get(key K) (e *list.Element[item[K, V]], ok bool) {
-	e, ok = c.live.tbl[key]
+// get returns the key's live slot, if any, and the segment in which it was found.
+func (c *Cache[K, V]) get(key K) (i int32, seg Segment, ok bool) {
+	i, ok = c.live(key)
 	if !ok {
 		// Live cache miss.
-		return nil, false
+		return 0, 0, false
+	}
+	if c.expired(c.items.slots[i].exp) {
+		// Live cache hit, but expired.
+		it := c.items.remove(i)
+		c.evicted(it.key, it.val, EvictExpired)
+		c.gauge()
+		return 0, 0, false
 	}
 	// Live cache hit.
-	if e.Value.hot {
-		// Key is already hot.
-		c.live.mfu.MoveToFront(e)
-		return e, true
-	}
-	// Key is newly hot.
-	c.live.mru.Remove(e)
-	e = c.live.mfu.PushFront(item[K, V]{
-		key: key,
-		val: e.Value.val,
-		hot: true,
-	})
-	c.live.tbl[key] = e
-	return e, true
-}
-
-// evict clears space, if necessary, by moving an item from the live cache to the dead cache
-// and/or dropping items from the dead cache. hot gives preferential treatment to the MFU cache
-// when all else is equal.
-func (c *Cache[K, V]) evict(hot bool) {
-	if len(c.live.tbl) >= c.max {
-		mruLen := c.live.mru.Len()
-		mfuLen := c.live.mfu.Len()
-		live, dead := &c.live.mfu, &c.dead.mfu
-		if mruLen > 0 && (mruLen > c.pivot || (hot && mruLen == c.pivot) || mfuLen == 0) {
-			live, dead = &c.live.mru, &c.dead.mru
-		}
-		it := c.live.remove(live.Back())
-		c.dead.tbl[it.key] = dead.PushFront(item[K, empty]{
-			key: it.key,
-			hot: it.hot,
-		})
-	}
-	if len(c.dead.tbl) > c.max {
-		dead := &c.dead.mfu
-		if c.live.mru.Len()+c.dead.mru.Len() >= c.max {
-			dead = &c.dead.mru
-		}
-		c.dead.remove(dead.Back())
+	seg = c.items.slots[i].seg()
+	c.items.move(i, liveMFU)
+	if seg == MRU {
+		// Key is newly hot.
+		c.stats.promotions.Add(1)
+		c.gauge()
+	}
+	return i, seg, true
+}
+
+// live returns the key's live slot, if any.
+func (c *Cache[K, V]) live(key K) (int32, bool) {
+	i, ok := c.items.tbl[key]
+	return i, ok && c.items.slots[i].list < deadMRU
+}
+
+// dead returns the key's dead slot, if any.
+func (c *Cache[K, V]) dead(key K) (int32, bool) {
+	i, ok := c.items.tbl[key]
+	return i, ok && c.items.slots[i].list >= deadMRU
+}
+
+// weigh returns the weight of the key's value.
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 1
+	}
+	wt := c.weigher(key, value)
+	if wt <= 0 {
+		panic("arc: weight must be greater than 0")
+	}
+	return wt
+}
+
+func (c *Cache[K, V]) now() int64 {
+	return c.clock.Now().UnixNano()
+}
+
+func (c *Cache[K, V]) expired(exp int64) bool {
+	return exp != 0 && exp <= c.now()
+}
+
+// gauge records the current max, pivot, and sizes of the lists.
+// Stats loads them together, so they are consistent with each other.
+func (c *Cache[K, V]) gauge() {
+	c.stats.seq.Add(1)
+	c.stats.max.Store(c.max)
+	c.stats.pivot.Store(c.pivot)
+	c.stats.lens[MRU].Store(int64(c.items.lens[liveMRU]))
+	c.stats.lens[MFU].Store(int64(c.items.lens[liveMFU]))
+	c.stats.ghostLen[MRU].Store(int64(c.items.lens[deadMRU]))
+	c.stats.ghostLen[MFU].Store(int64(c.items.lens[deadMFU]))
+	c.stats.weights[MRU].Store(c.items.wt[liveMRU])
+	c.stats.weights[MFU].Store(c.items.wt[liveMFU])
+	c.stats.ghostWeights[MRU].Store(c.items.wt[deadMRU])
+	c.stats.ghostWeights[MFU].Store(c.items.wt[deadMFU])
+	c.stats.seq.Add(1)
+}
+
+// evicted counts the removal of a live item and calls the eviction function, if any.
+func (c *Cache[K, V]) evicted(key K, val V, cause EvictCause) {
+	switch cause {
+	case EvictCapacity:
+		c.stats.evictions.Add(1)
+	case EvictDeleted:
+		c.stats.deletions.Add(1)
+	case EvictExpired:
+		c.stats.expirations.Add(1)
+	}
+	if c.onEvict != nil {
+		c.onEvict(key, val, cause)
+	}
+}
+
+// ghostDropped calls the ghost drop hook, if any.
+func (c *Cache[K, V]) ghostDropped(it slot[K, V]) {
+	if c.ghost.Drop != nil {
+		c.ghost.Drop(it.key, it.seg())
+	}
+}
+
+// evict clears space for an item with the given weight, if necessary, by moving items
+// from the live cache to the dead cache and/or dropping items from the dead cache.
+// hot gives preferential treatment to the MFU cache when all else is equal.
+// Expired items are dropped rather than moved to the dead cache.
+func (c *Cache[K, V]) evict(hot bool, wt int64) {
+	for c.items.liveWeight()+wt > c.max {
+		c.evictLive(hot)
+	}
+	for c.items.deadWeight() > c.max {
+		c.evictDead()
 	}
 }
 
-func min(a, b int) int {
+// evictLive moves an item from the live cache to the dead cache.
+// See evict for details.
+func (c *Cache[K, V]) evictLive(hot bool) {
+	mruWt := c.items.wt[liveMRU]
+	mfuWt := c.items.wt[liveMFU]
+	l := int32(liveMFU)
+	if mruWt > 0 && (mruWt > c.pivot || (hot && mruWt == c.pivot) || mfuWt == 0) {
+		l = liveMRU
+	}
+	i := c.items.back(l)
+	p := &c.items.slots[i]
+	if c.expired(p.exp) {
+		it := c.items.remove(i)
+		c.evicted(it.key, it.val, EvictExpired)
+		return
+	}
+	// Keep the slot, but not the value.
+	key, val, seg := p.key, p.val, p.seg()
+	var zero V
+	p.val = zero
+	p.exp = 0
+	c.items.move(i, deadList(seg))
+	c.evicted(key, val, EvictCapacity)
+	if c.ghost.Add != nil {
+		c.ghost.Add(key, seg)
+	}
+}
+
+// evictDead drops an item from the dead cache.
+func (c *Cache[K, V]) evictDead() {
+	l := int32(deadMFU)
+	if mruWt := c.items.wt[deadMRU]; mruWt > 0 && c.items.wt[liveMRU]+mruWt >= c.max {
+		l = deadMRU
+	}
+	c.ghostDropped(c.items.remove(c.items.back(l)))
+}
+
+// scale returns v*num/den without overflow, given 0 <= v <= den and 0 <= num.
+func scale(v, num, den int64) int64 {
+	hi, lo := bits.Mul64(uint64(v), uint64(num))
+	q, _ := bits.Div64(hi, lo, uint64(den))
+	return int64(q)
+}
+
+func min(a, b int64) int64 {
 	if a < b {
 		return a
 	}
 	return b
 }
 
-func max(a, b int) int {
+func max(a, b int64) int64 {
 	if a > b {
 		return a
 	}
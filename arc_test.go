@@ -6,12 +6,14 @@ package arc
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"bursavich.dev/arc/arctest"
 	"bursavich.dev/arc/internal/list"
 )
 
@@ -19,10 +21,10 @@ type state[K comparable] [4][]K
 
 func cacheState[K comparable, V any](c *Cache[K, V]) state[K] {
 	return state[K]{
-		reverse(keys(&c.dead.mru)),
-		reverse(keys(&c.live.mru)),
-		keys(&c.live.mfu),
-		keys(&c.dead.mfu),
+		reverse(storeKeys(&c.items, deadMRU)),
+		reverse(storeKeys(&c.items, liveMRU)),
+		storeKeys(&c.items, liveMFU),
+		storeKeys(&c.items, deadMFU),
 	}
 }
 
@@ -58,6 +60,16 @@ func keys[K comparable, V any](l *list.List[item[K, V]]) []K {
 	return a
 }
 
+func storeKeys[K comparable, V any](s *store[K, V], l int32) []K {
+	a := make([]K, s.lens[l])
+	i := s.front(l)
+	for j := range a {
+		a[j] = s.slots[i].key
+		i = s.slots[i].next
+	}
+	return a
+}
+
 func reverse[K any](s []K) []K {
 	n := len(s)
 	for i := 0; i < n/2; i++ {
@@ -1176,3 +1188,348 @@ func TestGen(t *testing.T) {
 		}
 	}
 }
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		key   int
+		val   string
+		ttl   time.Duration
+		state state[int]
+	}{
+		{cmd: "set", key: 0, val: "0", state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "ttl", key: 1, val: "1", ttl: 0, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "get", key: 0, val: "0", state: state[int]{{}, {1}, {0}, {}}},
+		{cmd: "set", key: 2, val: "2", state: state[int]{{}, {1, 2}, {0}, {}}},
+		{cmd: "tick", ttl: 5 * time.Second, state: state[int]{{}, {1, 2}, {0}, {}}},
+		{cmd: "ttl", key: 2, val: "2", ttl: 20 * time.Second, state: state[int]{{}, {1}, {2, 0}, {}}},
+		{cmd: "tick", ttl: 5 * time.Second, state: state[int]{{}, {1}, {2, 0}, {}}},
+		{cmd: "get", key: 0, val: "", state: state[int]{{}, {1}, {2}, {}}},
+		{cmd: "get", key: 1, val: "1", state: state[int]{{}, {}, {1, 2}, {}}},
+		{cmd: "set", key: 3, val: "3", state: state[int]{{}, {3}, {1, 2}, {}}},
+		{cmd: "tick", ttl: 15 * time.Second, state: state[int]{{}, {3}, {1, 2}, {}}},
+		{cmd: "set", key: 4, val: "4", state: state[int]{{}, {3, 4}, {1}, {}}},
+		{cmd: "get", key: 3, val: "", state: state[int]{{}, {4}, {1}, {}}},
+		{cmd: "set", key: 5, val: "5", state: state[int]{{}, {4, 5}, {1}, {}}},
+		{cmd: "set", key: 6, val: "6", state: state[int]{{4}, {5, 6}, {1}, {}}},
+		{cmd: "set", key: 3, val: "3", state: state[int]{{4, 5}, {6, 3}, {1}, {}}},
+		{cmd: "set", key: 4, val: "4", state: state[int]{{5}, {6, 3}, {4}, {1}}},
+		{cmd: "ttl", key: 6, val: "6", ttl: math.MaxInt64, state: state[int]{{5}, {3}, {6, 4}, {1}}},
+		{cmd: "tick", ttl: time.Hour, state: state[int]{{5}, {3}, {6, 4}, {1}}},
+		{cmd: "get", key: 6, val: "6", state: state[int]{{5}, {3}, {6, 4}, {1}}},
+		{cmd: "get", key: 4, val: "", state: state[int]{{5}, {3}, {6}, {1}}},
+	}
+	clock := arctest.NewClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New[int, string](3, WithTTL(10*time.Second), WithClock(clock))
+	for i, tt := range tests {
+		var cmd string
+		switch tt.cmd {
+		case "get":
+			cmd = fmt.Sprintf("Get(%d)", tt.key)
+			if val, _ := c.Get(tt.key); tt.val != val {
+				t.Fatalf("step %d: %s: unexpected value; got: %q; want: %q", i, cmd, val, tt.val)
+			}
+		case "set":
+			cmd = fmt.Sprintf("Set(%d, %q)", tt.key, tt.val)
+			c.Set(tt.key, tt.val)
+		case "ttl":
+			cmd = fmt.Sprintf("SetWithTTL(%d, %q, %v)", tt.key, tt.val, tt.ttl)
+			c.SetWithTTL(tt.key, tt.val, tt.ttl)
+		case "del":
+			cmd = fmt.Sprintf("Delete(%d)", tt.key)
+			c.Delete(tt.key)
+		case "tick":
+			cmd = fmt.Sprintf("Advance(%v)", tt.ttl)
+			clock.Advance(tt.ttl)
+		default:
+			t.Fatalf("step %d: unexpected command: %q", i, tt.cmd)
+		}
+		if got := cacheState(c); !reflect.DeepEqual(got, tt.state) {
+			var prev state[int]
+			if i > 0 {
+				prev = tests[i-1].state
+			}
+			t.Fatalf("step %d: %s: unexpected state:\nprev %s\ngot  %s\nwant %s", i, cmd, prev, got, tt.state)
+		}
+	}
+
+	// A default time-to-live that overflows means never.
+	c = New[int, string](1, WithTTL(math.MaxInt64), WithClock(clock))
+	c.Set(0, "0")
+	if val, ok := c.Get(0); !ok || val != "0" {
+		t.Fatalf("Get(0) with max default TTL: got: (%q, %v); want: (%q, true)", val, ok, "0")
+	}
+}
+
+func TestPeek(t *testing.T) {
+	clock := arctest.NewClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New[int, string](2, WithClock(clock))
+	c.Set(0, "0")
+	c.Set(1, "1")
+	c.Set(2, "2")
+	c.SetWithTTL(3, "3", time.Second)
+	clock.Advance(time.Second)
+	want := cacheState(c)
+	stats := c.Stats()
+
+	tests := []struct {
+		key      int
+		val      string
+		found    bool
+		recently bool
+	}{
+		{key: 0, recently: true},
+		{key: 1, recently: true},
+		{key: 2, val: "2", found: true},
+		{key: 3},
+		{key: 4},
+	}
+	for _, tt := range tests {
+		if val, found := c.Peek(tt.key); val != tt.val || found != tt.found {
+			t.Errorf("Peek(%d): got: (%q, %v); want: (%q, %v)", tt.key, val, found, tt.val, tt.found)
+		}
+		if found := c.Contains(tt.key); found != tt.found {
+			t.Errorf("Contains(%d): got: %v; want: %v", tt.key, found, tt.found)
+		}
+		if recently := c.WasRecentlyEvicted(tt.key); recently != tt.recently {
+			t.Errorf("WasRecentlyEvicted(%d): got: %v; want: %v", tt.key, recently, tt.recently)
+		}
+	}
+	if got := cacheState(c); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	if got := c.Stats(); got != stats {
+		t.Fatalf("unexpected stats:\ngot:  %+v\nwant: %+v", got, stats)
+	}
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		key   int
+		val   string
+		pivot int64
+		state state[int]
+	}{
+		{cmd: "set", key: 0, val: "0", pivot: 3, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, val: "1", pivot: 3, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, val: "2", pivot: 3, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "set", key: 3, val: "3", pivot: 3, state: state[int]{{}, {0, 1, 2, 3}, {}, {}}},
+		{cmd: "set", key: 4, val: "4", pivot: 3, state: state[int]{{}, {0, 1, 2, 3, 4}, {}, {}}},
+		{cmd: "set", key: 5, val: "5", pivot: 3, state: state[int]{{}, {0, 1, 2, 3, 4, 5}, {}, {}}},
+		{cmd: "set", key: 6, val: "6", pivot: 3, state: state[int]{{0}, {1, 2, 3, 4, 5, 6}, {}, {}}},
+		{cmd: "set", key: 7, val: "7", pivot: 3, state: state[int]{{0, 1}, {2, 3, 4, 5, 6, 7}, {}, {}}},
+		{cmd: "set", key: 0, val: "0", pivot: 4, state: state[int]{{1, 2}, {3, 4, 5, 6, 7}, {0}, {}}},
+		{cmd: "set", key: 1, val: "1", pivot: 5, state: state[int]{{2}, {3, 4, 5, 6, 7}, {1}, {0}}},
+		{cmd: "get", key: 6, val: "6", pivot: 5, state: state[int]{{2}, {3, 4, 5, 7}, {6, 1}, {0}}},
+		{cmd: "get", key: 7, val: "7", pivot: 5, state: state[int]{{2}, {3, 4, 5}, {7, 6, 1}, {0}}},
+		{cmd: "resize", key: 3, pivot: 2, state: state[int]{{}, {4, 5}, {7}, {6, 1, 0}}},
+		{cmd: "set", key: 2, val: "2", pivot: 2, state: state[int]{{}, {4, 5, 2}, {}, {7, 6, 1}}},
+		{cmd: "set", key: 8, val: "8", pivot: 2, state: state[int]{{}, {5, 2, 8}, {}, {7, 6, 1}}},
+		{cmd: "resize", key: 8, pivot: 5, state: state[int]{{}, {5, 2, 8}, {}, {7, 6, 1}}},
+		{cmd: "set", key: 9, val: "9", pivot: 5, state: state[int]{{}, {5, 2, 8, 9}, {}, {7, 6, 1}}},
+		{cmd: "set", key: 10, val: "10", pivot: 5, state: state[int]{{}, {5, 2, 8, 9, 10}, {}, {7, 6, 1}}},
+		{cmd: "set", key: 3, val: "3", pivot: 5, state: state[int]{{}, {5, 2, 8, 9, 10, 3}, {}, {7, 6, 1}}},
+		{cmd: "set", key: 4, val: "4", pivot: 5, state: state[int]{{}, {5, 2, 8, 9, 10, 3, 4}, {}, {7, 6, 1}}},
+		{cmd: "get", key: 9, val: "9", pivot: 5, state: state[int]{{}, {5, 2, 8, 10, 3, 4}, {9}, {7, 6, 1}}},
+		{cmd: "resize", key: 2, pivot: 1, state: state[int]{{}, {4}, {9}, {7, 6}}},
+		{cmd: "set", key: 11, val: "11", pivot: 1, state: state[int]{{}, {4, 11}, {}, {9, 7}}},
+		{cmd: "set", key: 10, val: "10", pivot: 1, state: state[int]{{}, {11, 10}, {}, {9, 7}}},
+		{cmd: "resize", key: 1, pivot: 0, state: state[int]{{}, {10}, {}, {9}}},
+		{cmd: "set", key: 12, val: "12", pivot: 0, state: state[int]{{}, {12}, {}, {9}}},
+		{cmd: "resize", key: 4, pivot: 0, state: state[int]{{}, {12}, {}, {9}}},
+		{cmd: "set", key: 13, val: "13", pivot: 0, state: state[int]{{}, {12, 13}, {}, {9}}},
+	}
+	c := New[int, string](6)
+	for i, tt := range tests {
+		var cmd string
+		switch tt.cmd {
+		case "get":
+			cmd = fmt.Sprintf("Get(%d)", tt.key)
+			if val, _ := c.Get(tt.key); tt.val != val {
+				t.Fatalf("step %d: %s: unexpected value; got: %q; want: %q", i, cmd, val, tt.val)
+			}
+		case "set":
+			cmd = fmt.Sprintf("Set(%d, %q)", tt.key, tt.val)
+			c.Set(tt.key, tt.val)
+		case "resize":
+			cmd = fmt.Sprintf("Resize(%d)", tt.key)
+			c.Resize(tt.key)
+		default:
+			t.Fatalf("step %d: unexpected command: %q", i, tt.cmd)
+		}
+		if got := cacheState(c); !reflect.DeepEqual(got, tt.state) {
+			var prev state[int]
+			if i > 0 {
+				prev = tests[i-1].state
+			}
+			t.Fatalf("step %d: %s: unexpected state:\nprev %s\ngot  %s\nwant %s", i, cmd, prev, got, tt.state)
+		}
+		if c.pivot != tt.pivot {
+			t.Fatalf("step %d: %s: unexpected pivot; got: %d; want: %d", i, cmd, c.pivot, tt.pivot)
+		}
+	}
+	if s := c.Stats(); s.Max != 4 || s.Pivot != 0 {
+		t.Fatalf("unexpected stats: max: %d; pivot: %d", s.Max, s.Pivot)
+	}
+
+	// Weight budgets may be large enough for pivot*size to overflow.
+	w := New[int, string](8<<30, WithWeigher(func(key int, val string) int64 { return 1 << 30 }))
+	w.pivot = 4 << 30
+	for _, tt := range []struct{ size, pivot int64 }{
+		{size: 16 << 30, pivot: 8 << 30},
+		{size: 1<<62 + 1<<61, pivot: 1<<61 + 1<<60},
+		{size: 3, pivot: 1},
+	} {
+		w.Resize(int(tt.size))
+		if w.pivot != tt.pivot {
+			t.Fatalf("Resize(%d): unexpected pivot: got: %d; want: %d", tt.size, w.pivot, tt.pivot)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	var events []string
+	newCache := func() *Cache[int, string] {
+		c := New[int, string](2,
+			WithEvictFunc(func(key int, val string, cause EvictCause) {
+				events = append(events, fmt.Sprintf("evict %d %v", key, cause))
+			}),
+			WithGhostHooks(GhostHooks[int]{
+				Drop: func(key int, seg Segment) {
+					events = append(events, fmt.Sprintf("drop %d %v", key, seg))
+				},
+			}),
+		)
+		c.Set(0, "0")
+		c.Set(1, "1")
+		c.Get(0)
+		c.Set(2, "2")
+		c.Set(3, "3")
+		c.Set(1, "1")
+		if got, want := cacheState(c), (state[int]{{2}, {3}, {1}, {0}}); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+		}
+		if c.pivot != 2 {
+			t.Fatalf("unexpected pivot: got: %d; want: 2", c.pivot)
+		}
+		events = nil
+		return c
+	}
+	tests := []struct {
+		name   string
+		op     func(*Cache[int, string])
+		events []string
+		pivot  int64
+		state  state[int]
+	}{
+		{
+			name:   "Clear",
+			op:     (*Cache[int, string]).Clear,
+			events: []string{"evict 3 cleared", "evict 1 cleared"},
+			pivot:  2,
+			state:  state[int]{{2}, {}, {}, {0}},
+		},
+		{
+			name:   "Purge",
+			op:     (*Cache[int, string]).Purge,
+			events: []string{"evict 3 cleared", "evict 1 cleared", "drop 2 mru", "drop 0 mfu"},
+			pivot:  1,
+			state:  state[int]{{}, {}, {}, {}},
+		},
+	}
+	for _, tt := range tests {
+		c := newCache()
+		tt.op(c)
+		if !reflect.DeepEqual(events, tt.events) {
+			t.Errorf("%s: unexpected events: got: %q; want: %q", tt.name, events, tt.events)
+		}
+		if got := cacheState(c); !reflect.DeepEqual(got, tt.state) {
+			t.Errorf("%s: unexpected state: got: %v; want: %v", tt.name, got, tt.state)
+		}
+		if c.pivot != tt.pivot {
+			t.Errorf("%s: unexpected pivot: got: %d; want: %d", tt.name, c.pivot, tt.pivot)
+		}
+		if c.Len() != 0 || c.Stats().Len() != 0 {
+			t.Errorf("%s: unexpected len: %d; stats: %d", tt.name, c.Len(), c.Stats().Len())
+		}
+		// The cache still works.
+		c.Set(2, "2")
+		if val, ok := c.Get(2); !ok || val != "2" {
+			t.Errorf("%s: Get(2): got: (%q, %v); want: (%q, true)", tt.name, val, ok, "2")
+		}
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	const size = 1 << 10
+	newCache := func() *Cache[int, int] {
+		// Fill the live and dead caches, so new keys are steady-state misses.
+		c := New[int, int](size)
+		for i := 0; i < 2*size; i++ {
+			c.Set(i, i)
+		}
+		return c
+	}
+	tests := []struct {
+		name string
+		op   func(c *Cache[int, int], i int)
+	}{
+		{
+			name: "Get/MRU",
+			op:   func(c *Cache[int, int], i int) { c.Get(size + i) },
+		},
+		{
+			name: "Get/MFU",
+			op:   func(c *Cache[int, int], i int) { c.Get(2*size - 1) },
+		},
+		{
+			name: "Get/Miss",
+			op:   func(c *Cache[int, int], i int) { c.Get(-1) },
+		},
+		{
+			name: "Set/Live",
+			op:   func(c *Cache[int, int], i int) { c.Set(2*size-1, i) },
+		},
+		{
+			name: "Set/Dead",
+			op:   func(c *Cache[int, int], i int) { c.Set(i, i) },
+		},
+		{
+			name: "Set/Miss",
+			op:   func(c *Cache[int, int], i int) { c.Set(3*size+i, i) },
+		},
+	}
+	for _, tt := range tests {
+		c := newCache()
+		c.Get(2*size - 1)
+		var i int
+		allocs := testing.AllocsPerRun(size/2, func() {
+			tt.op(c, i)
+			i++
+		})
+		if allocs != 0 {
+			t.Errorf("%s: unexpected allocs: got: %v; want: 0", tt.name, allocs)
+		}
+	}
+}
+
+func BenchmarkCache(b *testing.B) {
+	const (
+		size = 1 << 16
+		keys = size * 2
+	)
+	c := New[int, int](size)
+	for i := 0; i < keys; i++ {
+		c.Set(i, i)
+	}
+	rng := rand.New(rand.NewSource(1))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := rng.Intn(keys)
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, key)
+		}
+	}
+}
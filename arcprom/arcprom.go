@@ -0,0 +1,232 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Package arcprom exports cache statistics in the Prometheus text exposition format.
+//
+// See:
+//
+//	https://prometheus.io/docs/instrumenting/exposition_formats/
+package arcprom
+
+import (
+	"bufio"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bursavich.dev/arc"
+)
+
+// A Source is a cache that reports statistics.
+type Source interface {
+	Stats() arc.Stats
+}
+
+// Exporter is an http.Handler that serves the statistics of named caches.
+// It is safe for concurrent access.
+type Exporter struct {
+	mu     sync.Mutex
+	caches map[string]Source
+}
+
+// NewExporter returns a new Exporter.
+func NewExporter() *Exporter {
+	return &Exporter{caches: make(map[string]Source)}
+}
+
+// Register adds the named cache to the exporter, replacing any cache with the same name.
+func (e *Exporter) Register(name string, src Source) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.caches[name] = src
+}
+
+// Unregister removes the named cache from the exporter.
+func (e *Exporter) Unregister(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.caches, name)
+}
+
+type metric struct {
+	name  string
+	typ   string
+	help  string
+	label string   // optional label name
+	vals  []string // label values
+	value func(s *arc.Stats, val string) float64
+}
+
+var metrics = []metric{
+	{
+		name:  "arc_hits_total",
+		typ:   "counter",
+		help:  "Gets of live keys.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRUHits)
+			}
+			return float64(s.MFUHits)
+		},
+	},
+	{
+		name:  "arc_misses_total",
+		typ:   "counter",
+		help:  "Gets of keys that were not live.",
+		value: func(s *arc.Stats, _ string) float64 { return float64(s.Misses) },
+	},
+	{
+		name:  "arc_ghost_hits_total",
+		typ:   "counter",
+		help:  "Sets of keys in the dead lists.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRUGhostHits)
+			}
+			return float64(s.MFUGhostHits)
+		},
+	},
+	{
+		name:  "arc_promotions_total",
+		typ:   "counter",
+		help:  "Live keys moved from the MRU list to the MFU list.",
+		value: func(s *arc.Stats, _ string) float64 { return float64(s.Promotions) },
+	},
+	{
+		name:  "arc_removals_total",
+		typ:   "counter",
+		help:  "Live keys removed from the cache.",
+		label: "cause",
+		vals:  []string{"capacity", "expired", "deleted"},
+		value: func(s *arc.Stats, cause string) float64 {
+			switch cause {
+			case "capacity":
+				return float64(s.Evictions)
+			case "expired":
+				return float64(s.Expirations)
+			}
+			return float64(s.Deletions)
+		},
+	},
+	{
+		name:  "arc_live_size",
+		typ:   "gauge",
+		help:  "Size of the live lists.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRULen)
+			}
+			return float64(s.MFULen)
+		},
+	},
+	{
+		name:  "arc_dead_size",
+		typ:   "gauge",
+		help:  "Size of the dead lists.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRUGhostLen)
+			}
+			return float64(s.MFUGhostLen)
+		},
+	},
+	{
+		name:  "arc_live_weight",
+		typ:   "gauge",
+		help:  "Total weight of the live lists.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRUWeight)
+			}
+			return float64(s.MFUWeight)
+		},
+	},
+	{
+		name:  "arc_dead_weight",
+		typ:   "gauge",
+		help:  "Total weight of the dead lists.",
+		label: "segment",
+		vals:  []string{"mru", "mfu"},
+		value: func(s *arc.Stats, seg string) float64 {
+			if seg == "mru" {
+				return float64(s.MRUGhostWeight)
+			}
+			return float64(s.MFUGhostWeight)
+		},
+	},
+	{
+		name:  "arc_max_size",
+		typ:   "gauge",
+		help:  "Maximum size, or total weight, of the live lists.",
+		value: func(s *arc.Stats, _ string) float64 { return float64(s.Max) },
+	},
+	{
+		name:  "arc_pivot",
+		typ:   "gauge",
+		help:  "Target size, or total weight, of the live MRU list.",
+		value: func(s *arc.Stats, _ string) float64 { return float64(s.Pivot) },
+	},
+}
+
+// ServeHTTP writes the statistics of the registered caches.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	names := make([]string, 0, len(e.caches))
+	stats := make(map[string]arc.Stats, len(e.caches))
+	for name, src := range e.caches {
+		names = append(names, name)
+		stats[name] = src.Stats()
+	}
+	e.mu.Unlock()
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		bw.WriteString("# HELP " + m.name + " " + m.help + "\n")
+		bw.WriteString("# TYPE " + m.name + " " + m.typ + "\n")
+		for _, name := range names {
+			s := stats[name]
+			if m.label == "" {
+				writeSample(bw, m.name, name, "", "", m.value(&s, ""))
+				continue
+			}
+			for _, v := range m.vals {
+				writeSample(bw, m.name, name, m.label, v, m.value(&s, v))
+			}
+		}
+	}
+	bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, metric, cache, label, value string, v float64) {
+	w.WriteString(metric)
+	w.WriteString(`{cache="`)
+	w.WriteString(escape(cache))
+	w.WriteByte('"')
+	if label != "" {
+		w.WriteString("," + label + `="` + value + `"`)
+	}
+	w.WriteString("} ")
+	w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	w.WriteByte('\n')
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arcprom
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bursavich.dev/arc"
+)
+
+func TestExporter(t *testing.T) {
+	c := arc.New[int, int](4)
+	c.Set(0, 0)
+	c.Get(0)
+	c.Get(0)
+	c.Get(1)
+	c.Set(1, 1)
+	c.Get(1)
+
+	e := NewExporter()
+	e.Register("users", c)
+	e.Register(`a"b`, arc.New[int, int](2))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got, want := rec.Header().Get("Content-Type"), "text/plain; version=0.0.4; charset=utf-8"; got != want {
+		t.Fatalf("unexpected content type: got: %q; want: %q", got, want)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# HELP arc_hits_total Gets of live keys.\n# TYPE arc_hits_total counter\n" +
+			`arc_hits_total{cache="a\"b",segment="mru"} 0` + "\n",
+		`arc_hits_total{cache="users",segment="mru"} 2` + "\n",
+		`arc_hits_total{cache="users",segment="mfu"} 1` + "\n",
+		`arc_misses_total{cache="users"} 1` + "\n",
+		`arc_promotions_total{cache="users"} 2` + "\n",
+		`arc_removals_total{cache="users",cause="capacity"} 0` + "\n",
+		`arc_live_size{cache="users",segment="mfu"} 2` + "\n",
+		`arc_dead_size{cache="users",segment="mru"} 0` + "\n",
+		`arc_live_weight{cache="users",segment="mfu"} 2` + "\n",
+		`arc_max_size{cache="users"} 4` + "\n",
+		`arc_pivot{cache="users"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+
+	e.Unregister("users")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if strings.Contains(rec.Body.String(), `cache="users"`) {
+		t.Errorf("unexpected unregistered cache in:\n%s", rec.Body.String())
+	}
+}
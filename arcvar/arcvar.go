@@ -0,0 +1,65 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Package arcvar publishes cache statistics with package expvar.
+package arcvar
+
+import (
+	"expvar"
+
+	"bursavich.dev/arc"
+)
+
+// A Source is a cache that reports statistics.
+type Source interface {
+	Stats() arc.Stats
+}
+
+type vars struct {
+	Size        int     `json:"size"`
+	Max         int64   `json:"max"`
+	Pivot       int64   `json:"pivot"`
+	MRULen      int     `json:"mru"`
+	MFULen      int     `json:"mfu"`
+	MRUGhostLen int     `json:"mru_ghost"`
+	MFUGhostLen int     `json:"mfu_ghost"`
+	Hits        uint64  `json:"hits"`
+	Misses      uint64  `json:"misses"`
+	GhostHits   uint64  `json:"ghost_hits"`
+	HitRatio    float64 `json:"hit_ratio"`
+	MRUHitRatio float64 `json:"mru_hit_ratio"`
+	MFUHitRatio float64 `json:"mfu_hit_ratio"`
+}
+
+// Var returns an expvar.Var that renders the cache's statistics as JSON.
+// It is safe to read while the cache is in use.
+func Var(src Source) expvar.Var {
+	return expvar.Func(func() any {
+		s := src.Stats()
+		v := vars{
+			Size:        s.Len(),
+			Max:         s.Max,
+			Pivot:       s.Pivot,
+			MRULen:      s.MRULen,
+			MFULen:      s.MFULen,
+			MRUGhostLen: s.MRUGhostLen,
+			MFUGhostLen: s.MFUGhostLen,
+			Hits:        s.Hits(),
+			Misses:      s.Misses,
+			GhostHits:   s.GhostHits(),
+			HitRatio:    s.HitRatio(),
+		}
+		if gets := s.Hits() + s.Misses; gets > 0 {
+			v.MRUHitRatio = float64(s.MRUHits) / float64(gets)
+			v.MFUHitRatio = float64(s.MFUHits) / float64(gets)
+		}
+		return v
+	})
+}
+
+// Publish publishes the cache's statistics as an exported variable with the given name.
+// Like expvar.Publish, it panics if the name is already registered.
+func Publish(name string, src Source) {
+	expvar.Publish(name, Var(src))
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arcvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc"
+)
+
+func TestPublish(t *testing.T) {
+	c := arc.NewSync[int, int](4)
+	c.Set(0, 0)
+	c.Set(1, 1)
+	c.Get(0)
+	c.Get(0)
+	c.Get(1)
+	c.Get(2)
+	Publish("cache", c)
+
+	v := expvar.Get("cache")
+	if v == nil {
+		t.Fatal("cache not published")
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v: %s", err, v.String())
+	}
+	want := map[string]any{
+		"size":          2.0,
+		"max":           4.0,
+		"pivot":         2.0,
+		"mru":           0.0,
+		"mfu":           2.0,
+		"mru_ghost":     0.0,
+		"mfu_ghost":     0.0,
+		"hits":          3.0,
+		"misses":        1.0,
+		"ghost_hits":    0.0,
+		"hit_ratio":     0.75,
+		"mru_hit_ratio": 0.5,
+		"mfu_hit_ratio": 0.25,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected vars:\ngot:  %v\nwant: %v", got, want)
+	}
+}
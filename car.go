@@ -0,0 +1,155 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+type carItem[K comparable, V any] struct {
+	key K
+	val V
+	hot bool   // in T2
+	ref uint32 // reference bit, accessed atomically
+}
+
+// CAR is a Clock with Adaptive Replacement cache, as described by Bansal and Modha.
+// It is safe for concurrent access.
+//
+// Like ARC, it adapts a pivot between recency and frequency using ghost lists
+// of recently evicted keys. Unlike ARC, its live items are kept in two clocks
+// rather than LRU lists, so a hit only sets the item's reference bit. Gets take
+// a shared lock and never contend with each other.
+//
+// See:
+//
+//	https://www.usenix.org/legacy/events/fast04/tech/full_papers/bansal/bansal.pdf
+type CAR[K comparable, V any] struct {
+	mu    sync.RWMutex
+	max   int64   // the paper's c
+	pivot float64 // the paper's p, the target length of T1
+	tbl   map[K]*list.Element[carItem[K, V]]
+	t1    list.List[carItem[K, V]] // clock of items seen once; the front is the hand
+	t2    list.List[carItem[K, V]] // clock of items seen more than once; the front is the hand
+	dead  subCache[K, empty]       // B1 and B2
+}
+
+// NewCAR creates a new CAR cache that holds up to size live items.
+func NewCAR[K comparable, V any](size int) *CAR[K, V] {
+	if size <= 0 {
+		panic("arc: size must be greater than 0")
+	}
+	c := &CAR[K, V]{
+		max: int64(size),
+		tbl: make(map[K]*list.Element[carItem[K, V]], size),
+	}
+	c.t1.Init()
+	c.t2.Init()
+	c.dead.init(size)
+	return c
+}
+
+// Len returns the number of live items in the cache.
+func (c *CAR[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.tbl)
+}
+
+// Get reads the key's value from the cache.
+func (c *CAR[K, V]) Get(key K) (value V, found bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.tbl[key]
+	if !ok {
+		return value, false
+	}
+	if atomic.LoadUint32(&e.Value.ref) == 0 {
+		atomic.StoreUint32(&e.Value.ref, 1)
+	}
+	return e.Value.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *CAR[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.tbl[key]; ok {
+		e.Value.val = value
+		atomic.StoreUint32(&e.Value.ref, 1)
+		return
+	}
+	g, ghost := c.dead.tbl[key]
+	if int64(len(c.tbl)) >= c.max {
+		c.replace()
+		if !ghost {
+			if int64(c.t1.Len()+c.dead.mru.Len()) >= c.max {
+				c.dead.remove(c.dead.mru.Back())
+			} else if int64(len(c.tbl)+len(c.dead.tbl)) >= 2*c.max {
+				c.dead.remove(c.dead.mfu.Back())
+			}
+		}
+	}
+	if !ghost {
+		c.tbl[key] = c.t1.PushBack(carItem[K, V]{key: key, val: value})
+		return
+	}
+	b1, b2 := float64(c.dead.mru.Len()), float64(c.dead.mfu.Len())
+	if g.Value.hot {
+		c.pivot = math.Max(c.pivot-math.Max(1, b1/b2), 0)
+	} else {
+		c.pivot = math.Min(c.pivot+math.Max(1, b2/b1), float64(c.max))
+	}
+	c.dead.remove(g)
+	c.tbl[key] = c.t2.PushBack(carItem[K, V]{key: key, val: value, hot: true})
+}
+
+// Delete deletes the key's value from the cache.
+func (c *CAR[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.tbl[key]; ok {
+		e.List().Remove(e)
+		delete(c.tbl, key)
+	} else if e, ok := c.dead.tbl[key]; ok {
+		c.dead.remove(e)
+	}
+}
+
+// replace advances the clock hands until it finds an item whose reference bit
+// is not set and moves it to the front of B1 or B2. Referenced items under
+// the T1 hand are moved to the back of T2, and those under the T2 hand are
+// passed over. Reference bits are cleared as the hands pass.
+func (c *CAR[K, V]) replace() {
+	for {
+		if t1 := float64(c.t1.Len()); t1 >= math.Max(1, c.pivot) || c.t2.Len() == 0 {
+			e := c.t1.Front()
+			it := c.t1.Remove(e)
+			if atomic.LoadUint32(&it.ref) == 0 {
+				delete(c.tbl, it.key)
+				c.dead.push(item[K, empty]{key: it.key})
+				return
+			}
+			it.ref, it.hot = 0, true
+			c.tbl[it.key] = c.t2.PushBack(it)
+		} else {
+			e := c.t2.Front()
+			if atomic.LoadUint32(&e.Value.ref) == 0 {
+				c.t2.Remove(e)
+				delete(c.tbl, e.Value.key)
+				c.dead.push(item[K, empty]{key: e.Value.key, hot: true})
+				return
+			}
+			atomic.StoreUint32(&e.Value.ref, 0)
+			c.t2.MoveToBack(e)
+		}
+	}
+}
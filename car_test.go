@@ -0,0 +1,133 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+// carState returns the keys of B1 from least to most recently evicted, of T1 and T2
+// from their hands to their tails, and of B2 from most to least recently evicted.
+func carState[K comparable, V any](c *CAR[K, V]) state[K] {
+	return state[K]{
+		reverse(keys(&c.dead.mru)),
+		carKeys(&c.t1),
+		carKeys(&c.t2),
+		keys(&c.dead.mfu),
+	}
+}
+
+func carKeys[K comparable, V any](l *list.List[carItem[K, V]]) []K {
+	a := make([]K, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		a = append(a, e.Value.key)
+	}
+	return a
+}
+
+func TestCAR(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		key   int
+		val   string
+		pivot float64
+		state state[int]
+	}{
+		{cmd: "set", key: 0, val: "0", pivot: 0, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, val: "1", pivot: 0, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, val: "2", pivot: 0, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "get", key: 0, val: "0", pivot: 0, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "set", key: 3, val: "3", pivot: 0, state: state[int]{{1}, {2, 3}, {0}, {}}},
+		{cmd: "set", key: 4, val: "4", pivot: 0, state: state[int]{{2}, {3, 4}, {0}, {}}},
+		{cmd: "set", key: 2, val: "2", pivot: 1, state: state[int]{{3}, {4}, {0, 2}, {}}},
+		{cmd: "get", key: 4, val: "4", pivot: 1, state: state[int]{{3}, {4}, {0, 2}, {}}},
+		{cmd: "get", key: 2, val: "2", pivot: 1, state: state[int]{{3}, {4}, {0, 2}, {}}},
+		{cmd: "get", key: 1, val: "", pivot: 1, state: state[int]{{3}, {4}, {0, 2}, {}}},
+		{cmd: "set", key: 5, val: "5", pivot: 1, state: state[int]{{3}, {5}, {2, 4}, {0}}},
+		{cmd: "set", key: 0, val: "0", pivot: 0, state: state[int]{{3, 5}, {}, {2, 4, 0}, {}}},
+		{cmd: "set", key: 6, val: "6", pivot: 0, state: state[int]{{3, 5}, {6}, {0, 2}, {4}}},
+		{cmd: "set", key: 7, val: "7", pivot: 0, state: state[int]{{5, 6}, {7}, {0, 2}, {4}}},
+		{cmd: "set", key: 8, val: "8", pivot: 0, state: state[int]{{6, 7}, {8}, {0, 2}, {4}}},
+		{cmd: "set", key: 2, val: "two", pivot: 0, state: state[int]{{6, 7}, {8}, {0, 2}, {4}}},
+		{cmd: "get", key: 2, val: "two", pivot: 0, state: state[int]{{6, 7}, {8}, {0, 2}, {4}}},
+		{cmd: "del", key: 8, pivot: 0, state: state[int]{{6, 7}, {}, {0, 2}, {4}}},
+		{cmd: "set", key: 9, val: "9", pivot: 0, state: state[int]{{6, 7}, {9}, {0, 2}, {4}}},
+		{cmd: "del", key: 4, pivot: 0, state: state[int]{{6, 7}, {9}, {0, 2}, {}}},
+	}
+	c := NewCAR[int, string](3)
+	for i, tt := range tests {
+		var cmd string
+		switch tt.cmd {
+		case "get":
+			cmd = fmt.Sprintf("Get(%d)", tt.key)
+			if val, _ := c.Get(tt.key); tt.val != val {
+				t.Fatalf("step %d: %s: unexpected value; got: %q; want: %q", i, cmd, val, tt.val)
+			}
+		case "set":
+			cmd = fmt.Sprintf("Set(%d, %q)", tt.key, tt.val)
+			c.Set(tt.key, tt.val)
+		case "del":
+			cmd = fmt.Sprintf("Delete(%d)", tt.key)
+			c.Delete(tt.key)
+		default:
+			t.Fatalf("step %d: unexpected command: %q", i, tt.cmd)
+		}
+		if got := carState(c); !reflect.DeepEqual(got, tt.state) {
+			var prev state[int]
+			if i > 0 {
+				prev = tests[i-1].state
+			}
+			t.Fatalf("step %d: %s: unexpected state:\nprev %s\ngot  %s\nwant %s", i, cmd, prev, got, tt.state)
+		}
+		if c.pivot != tt.pivot {
+			t.Fatalf("step %d: %s: unexpected pivot; got: %g; want: %g", i, cmd, c.pivot, tt.pivot)
+		}
+	}
+	if c.Len() != 3 {
+		t.Fatalf("unexpected length: got: %d; want: 3", c.Len())
+	}
+}
+
+func TestCARConcurrent(t *testing.T) {
+	const (
+		size       = 64
+		keys       = 256
+		goroutines = 8
+		ops        = 10000
+	)
+	c := NewCAR[int, string](size)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				key := int(rng.Int31n(keys))
+				switch rng.Int31n(8) {
+				case 0:
+					c.Delete(key)
+				case 1, 2:
+					c.Set(key, strconv.Itoa(key))
+				default:
+					if val, ok := c.Get(key); ok && val != strconv.Itoa(key) {
+						t.Errorf("Get(%d): got: %q; want: %q", key, val, strconv.Itoa(key))
+					}
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+	if n := c.Len(); n > size {
+		t.Fatalf("unexpected length: got: %d; want <= %d", n, size)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"math"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+// Classic is an adaptive replacement cache that implements the algorithm
+// exactly as described by Megiddo and Modha, for reproducing published results.
+// It is not safe for concurrent access.
+//
+// The live MRU and MFU lists are the paper's T1 and T2, and the dead MRU and MFU
+// lists are its B1 and B2. A hit in B1 increases the pivot (the paper's p) by
+// max(1, |B2|/|B1|) and a hit in B2 decreases it by max(1, |B1|/|B2|). As in the
+// paper, the pivot is real-valued and the ratios are not truncated.
+//
+// A Get that hits is a request for a key in T1 or T2. A Get that misses has no effect,
+// and the following Set of the key is the request for a key in B1, B2, or neither.
+// The paper does not permit deletion, so a Delete simply removes the key from whichever
+// list holds it and later requests continue to follow the paper's cases.
+type Classic[K comparable, V any] struct {
+	max   int64              // the paper's c
+	pivot float64            // the paper's p, the target length of T1
+	live  subCache[K, V]     // T1 and T2
+	dead  subCache[K, empty] // B1 and B2
+}
+
+// NewClassic creates a new Classic cache that holds up to size live items.
+func NewClassic[K comparable, V any](size int) *Classic[K, V] {
+	if size <= 0 {
+		panic("arc: size must be greater than 0")
+	}
+	c := &Classic[K, V]{max: int64(size)}
+	c.live.init(size)
+	c.dead.init(size)
+	return c
+}
+
+// Len returns the number of live items in the cache.
+func (c *Classic[K, V]) Len() int {
+	return len(c.live.tbl)
+}
+
+// Get reads the key's value from the cache.
+func (c *Classic[K, V]) Get(key K) (value V, found bool) {
+	e, ok := c.live.tbl[key]
+	if !ok {
+		return value, false
+	}
+	return c.hit(e).Value.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *Classic[K, V]) Set(key K, value V) {
+	// Case I: x is in T1 or T2.
+	if e, ok := c.live.tbl[key]; ok {
+		c.hit(e).Value.val = value
+		return
+	}
+	// Cases II and III: x is in B1 or B2.
+	if e, ok := c.dead.tbl[key]; ok {
+		b1, b2 := float64(c.dead.mru.Len()), float64(c.dead.mfu.Len())
+		inB2 := e.Value.hot
+		if inB2 {
+			c.pivot = math.Max(c.pivot-math.Max(1, b1/b2), 0)
+		} else {
+			c.pivot = math.Min(c.pivot+math.Max(1, b2/b1), float64(c.max))
+		}
+		c.dead.remove(e)
+		c.replace(inB2)
+		c.live.push(item[K, V]{key: key, val: value, hot: true})
+		return
+	}
+	// Case IV: x is in neither.
+	t1, b1 := int64(c.live.mru.Len()), int64(c.dead.mru.Len())
+	if l1 := t1 + b1; l1 >= c.max {
+		// Case A: L1 has exactly c pages.
+		if t1 < c.max {
+			c.dead.remove(c.dead.mru.Back())
+			c.replace(false)
+		} else {
+			c.live.remove(c.live.mru.Back())
+		}
+	} else if n := int64(len(c.live.tbl) + len(c.dead.tbl)); n >= c.max {
+		// Case B: L1 has less than c pages.
+		if n >= 2*c.max {
+			c.dead.remove(c.dead.mfu.Back())
+		}
+		c.replace(false)
+	}
+	c.live.push(item[K, V]{key: key, val: value})
+}
+
+// Delete deletes the key's value from the cache.
+func (c *Classic[K, V]) Delete(key K) {
+	if e, ok := c.live.tbl[key]; ok {
+		c.live.remove(e)
+	} else if e, ok := c.dead.tbl[key]; ok {
+		c.dead.remove(e)
+	}
+}
+
+// hit moves the live item to the front of T2.
+func (c *Classic[K, V]) hit(e *list.Element[item[K, V]]) *list.Element[item[K, V]] {
+	if e.Value.hot {
+		c.live.mfu.MoveToFront(e)
+		return e
+	}
+	it := c.live.remove(e)
+	it.hot = true
+	return c.live.push(it)
+}
+
+// replace is the paper's REPLACE subroutine. It moves the LRU item of T1 or T2
+// to the MRU position of B1 or B2, respectively, if the cache is full.
+func (c *Classic[K, V]) replace(inB2 bool) {
+	if int64(len(c.live.tbl)) < c.max {
+		return // only after deletions
+	}
+	t1, t2 := float64(c.live.mru.Len()), c.live.mfu.Len()
+	l := &c.live.mfu
+	if t1 > 0 && ((inB2 && t1 == c.pivot) || t1 > c.pivot || t2 == 0) {
+		l = &c.live.mru
+	}
+	it := c.live.remove(l.Back())
+	c.dead.push(item[K, empty]{key: it.key, hot: it.hot})
+}
+
+type empty struct{}
+
+type item[K comparable, V any] struct {
+	key K
+	val V
+	hot bool
+}
+
+// subCache holds items in linked lists of pointers.
+// It's the storage of Classic and CAR.
+type subCache[K comparable, V any] struct {
+	tbl map[K]*list.Element[item[K, V]]
+	mru list.List[item[K, V]]
+	mfu list.List[item[K, V]]
+}
+
+func (c *subCache[K, V]) init(size int) {
+	c.tbl = make(map[K]*list.Element[item[K, V]], size)
+	c.mru.Init()
+	c.mfu.Init()
+}
+
+// push inserts the item at the front of the MFU list if it's hot,
+// or at the front of the MRU list otherwise.
+func (c *subCache[K, V]) push(it item[K, V]) *list.Element[item[K, V]] {
+	l := &c.mru
+	if it.hot {
+		l = &c.mfu
+	}
+	e := l.PushFront(it)
+	c.tbl[it.key] = e
+	return e
+}
+
+func (c *subCache[K, V]) remove(e *list.Element[item[K, V]]) item[K, V] {
+	e.List().Remove(e)
+	delete(c.tbl, e.Value.key)
+	return e.Value
+}
@@ -0,0 +1,169 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func classicState[K comparable, V any](c *Classic[K, V]) state[K] {
+	return state[K]{
+		reverse(keys(&c.dead.mru)),
+		reverse(keys(&c.live.mru)),
+		keys(&c.live.mfu),
+		keys(&c.dead.mfu),
+	}
+}
+
+type classicStep struct {
+	cmd   string
+	key   int
+	val   string
+	pivot float64
+	state state[int]
+}
+
+func testClassicSteps(t *testing.T, c *Classic[int, string], tests []classicStep) {
+	t.Helper()
+	for i, tt := range tests {
+		var cmd string
+		switch tt.cmd {
+		case "get":
+			cmd = fmt.Sprintf("Get(%d)", tt.key)
+			if val, _ := c.Get(tt.key); tt.val != val {
+				t.Fatalf("step %d: %s: unexpected value; got: %q; want: %q", i, cmd, val, tt.val)
+			}
+		case "set":
+			cmd = fmt.Sprintf("Set(%d, %q)", tt.key, tt.val)
+			c.Set(tt.key, tt.val)
+		case "del":
+			cmd = fmt.Sprintf("Delete(%d)", tt.key)
+			c.Delete(tt.key)
+		default:
+			t.Fatalf("step %d: unexpected command: %q", i, tt.cmd)
+		}
+		if got := classicState(c); !reflect.DeepEqual(got, tt.state) {
+			var prev state[int]
+			if i > 0 {
+				prev = tests[i-1].state
+			}
+			t.Fatalf("step %d: %s: unexpected state:\nprev %s\ngot  %s\nwant %s", i, cmd, prev, got, tt.state)
+		}
+		if c.pivot != tt.pivot {
+			t.Fatalf("step %d: %s: unexpected pivot; got: %g; want: %g", i, cmd, c.pivot, tt.pivot)
+		}
+	}
+}
+
+func TestClassic(t *testing.T) {
+	tests := []classicStep{
+		{cmd: "set", key: 0, val: "0", pivot: 0, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, val: "1", pivot: 0, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, val: "2", pivot: 0, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "set", key: 3, val: "3", pivot: 0, state: state[int]{{}, {0, 1, 2, 3}, {}, {}}},
+		{cmd: "get", key: 0, val: "0", pivot: 0, state: state[int]{{}, {1, 2, 3}, {0}, {}}},
+		{cmd: "get", key: 1, val: "1", pivot: 0, state: state[int]{{}, {2, 3}, {1, 0}, {}}},
+		{cmd: "set", key: 4, val: "4", pivot: 0, state: state[int]{{2}, {3, 4}, {1, 0}, {}}},
+		{cmd: "set", key: 5, val: "5", pivot: 0, state: state[int]{{2, 3}, {4, 5}, {1, 0}, {}}},
+		{cmd: "set", key: 6, val: "6", pivot: 0, state: state[int]{{3, 4}, {5, 6}, {1, 0}, {}}},
+		{cmd: "set", key: 3, val: "3", pivot: 1, state: state[int]{{4, 5}, {6}, {3, 1, 0}, {}}},
+		{cmd: "set", key: 7, val: "7", pivot: 1, state: state[int]{{4, 5}, {6, 7}, {3, 1}, {0}}},
+		{cmd: "set", key: 8, val: "8", pivot: 1, state: state[int]{{5, 6}, {7, 8}, {3, 1}, {0}}},
+		{cmd: "get", key: 7, val: "7", pivot: 1, state: state[int]{{5, 6}, {8}, {7, 3, 1}, {0}}},
+		{cmd: "set", key: 9, val: "9", pivot: 1, state: state[int]{{5, 6}, {8, 9}, {7, 3}, {1, 0}}},
+		{cmd: "get", key: 8, val: "8", pivot: 1, state: state[int]{{5, 6}, {9}, {8, 7, 3}, {1, 0}}},
+		{cmd: "set", key: 10, val: "10", pivot: 1, state: state[int]{{5, 6}, {9, 10}, {8, 7}, {3, 1}}},
+		{cmd: "set", key: 3, val: "3", pivot: 0, state: state[int]{{5, 6, 9}, {10}, {3, 8, 7}, {1}}},
+		{cmd: "set", key: 5, val: "5", pivot: 1, state: state[int]{{6, 9}, {10}, {5, 3, 8}, {7, 1}}},
+		{cmd: "set", key: 6, val: "6", pivot: 2, state: state[int]{{9}, {10}, {6, 5, 3}, {8, 7, 1}}},
+		{cmd: "set", key: 9, val: "9", pivot: 4, state: state[int]{{}, {10}, {9, 6, 5}, {3, 8, 7, 1}}},
+		{cmd: "set", key: 1, val: "1", pivot: 3, state: state[int]{{}, {10}, {1, 9, 6}, {5, 3, 8, 7}}},
+		{cmd: "set", key: 11, val: "11", pivot: 3, state: state[int]{{}, {10, 11}, {1, 9}, {6, 5, 3, 8}}},
+		{cmd: "set", key: 12, val: "12", pivot: 3, state: state[int]{{}, {10, 11, 12}, {1}, {9, 6, 5, 3}}},
+		{cmd: "set", key: 13, val: "13", pivot: 3, state: state[int]{{}, {10, 11, 12, 13}, {}, {1, 9, 6, 5}}},
+		{cmd: "set", key: 14, val: "14", pivot: 3, state: state[int]{{}, {11, 12, 13, 14}, {}, {1, 9, 6, 5}}},
+		{cmd: "get", key: 10, val: "", pivot: 3, state: state[int]{{}, {11, 12, 13, 14}, {}, {1, 9, 6, 5}}},
+		{cmd: "set", key: 9, val: "9", pivot: 2, state: state[int]{{11}, {12, 13, 14}, {9}, {1, 6, 5}}},
+		{cmd: "set", key: 6, val: "6", pivot: 1, state: state[int]{{11, 12}, {13, 14}, {6, 9}, {1, 5}}},
+		{cmd: "set", key: 5, val: "5", pivot: 0, state: state[int]{{11, 12, 13}, {14}, {5, 6, 9}, {1}}},
+		{cmd: "set", key: 1, val: "1", pivot: 0, state: state[int]{{11, 12, 13, 14}, {}, {1, 5, 6, 9}, {}}},
+		{cmd: "set", key: 11, val: "11", pivot: 1, state: state[int]{{12, 13, 14}, {}, {11, 1, 5, 6}, {9}}},
+		{cmd: "del", key: 12, pivot: 1, state: state[int]{{13, 14}, {}, {11, 1, 5, 6}, {9}}},
+		{cmd: "del", key: 1, pivot: 1, state: state[int]{{13, 14}, {}, {11, 5, 6}, {9}}},
+		{cmd: "set", key: 15, val: "15", pivot: 1, state: state[int]{{13, 14}, {15}, {11, 5, 6}, {9}}},
+	}
+	c := NewClassic[int, string](4)
+	testClassicSteps(t, c, tests)
+	if c.Len() != 4 {
+		t.Fatalf("unexpected length: got: %d; want: 4", c.Len())
+	}
+}
+
+// TestClassicFractionalPivot checks that the pivot moves by the exact ratio
+// of the ghost list lengths. With a size of 4, the ratio is always whole.
+func TestClassicFractionalPivot(t *testing.T) {
+	tests := []classicStep{
+		{cmd: "set", key: 0, val: "0", pivot: 0, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, val: "1", pivot: 0, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, val: "2", pivot: 0, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "set", key: 3, val: "3", pivot: 0, state: state[int]{{}, {0, 1, 2, 3}, {}, {}}},
+		{cmd: "set", key: 4, val: "4", pivot: 0, state: state[int]{{}, {0, 1, 2, 3, 4}, {}, {}}},
+		{cmd: "get", key: 0, val: "0", pivot: 0, state: state[int]{{}, {1, 2, 3, 4}, {0}, {}}},
+		{cmd: "get", key: 1, val: "1", pivot: 0, state: state[int]{{}, {2, 3, 4}, {1, 0}, {}}},
+		{cmd: "get", key: 2, val: "2", pivot: 0, state: state[int]{{}, {3, 4}, {2, 1, 0}, {}}},
+		{cmd: "get", key: 3, val: "3", pivot: 0, state: state[int]{{}, {4}, {3, 2, 1, 0}, {}}},
+		{cmd: "get", key: 4, val: "4", pivot: 0, state: state[int]{{}, {}, {4, 3, 2, 1, 0}, {}}},
+		{cmd: "set", key: 5, val: "5", pivot: 0, state: state[int]{{}, {5}, {4, 3, 2, 1}, {0}}},
+		{cmd: "get", key: 5, val: "5", pivot: 0, state: state[int]{{}, {}, {5, 4, 3, 2, 1}, {0}}},
+		{cmd: "set", key: 6, val: "6", pivot: 0, state: state[int]{{}, {6}, {5, 4, 3, 2}, {1, 0}}},
+		{cmd: "get", key: 6, val: "6", pivot: 0, state: state[int]{{}, {}, {6, 5, 4, 3, 2}, {1, 0}}},
+		{cmd: "set", key: 7, val: "7", pivot: 0, state: state[int]{{}, {7}, {6, 5, 4, 3}, {2, 1, 0}}},
+		{cmd: "get", key: 7, val: "7", pivot: 0, state: state[int]{{}, {}, {7, 6, 5, 4, 3}, {2, 1, 0}}},
+		{cmd: "set", key: 8, val: "8", pivot: 0, state: state[int]{{}, {8}, {7, 6, 5, 4}, {3, 2, 1, 0}}},
+		{cmd: "set", key: 9, val: "9", pivot: 0, state: state[int]{{8}, {9}, {7, 6, 5, 4}, {3, 2, 1, 0}}},
+		{cmd: "set", key: 10, val: "10", pivot: 0, state: state[int]{{8, 9}, {10}, {7, 6, 5, 4}, {3, 2, 1}}},
+		// |B2|/|B1| = 3/2
+		{cmd: "set", key: 8, val: "8", pivot: 1.5, state: state[int]{{9}, {10}, {8, 7, 6, 5}, {4, 3, 2, 1}}},
+		{cmd: "set", key: 11, val: "11", pivot: 1.5, state: state[int]{{9}, {10, 11}, {8, 7, 6}, {5, 4, 3, 2}}},
+		{cmd: "set", key: 12, val: "12", pivot: 1.5, state: state[int]{{9, 10}, {11, 12}, {8, 7, 6}, {5, 4, 3}}},
+		// |B1|/|B2| = 2/3
+		{cmd: "set", key: 5, val: "5", pivot: 0.5, state: state[int]{{9, 10, 11}, {12}, {5, 8, 7, 6}, {4, 3}}},
+	}
+	c := NewClassic[int, string](5)
+	testClassicSteps(t, c, tests)
+}
+
+// TestClassicInvariants checks the invariants stated in the paper
+// against a long pseudorandom sequence of requests.
+func TestClassicInvariants(t *testing.T) {
+	const size = 8
+	c := NewClassic[int, int](size)
+	for i := 0; i < 10000; i++ {
+		// A cheap deterministic sequence that mixes a hot set with a wide scan.
+		key := (i * 7919) % 97
+		if i%3 == 0 {
+			key %= 5
+		}
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, key)
+		}
+		t1, t2 := c.live.mru.Len(), c.live.mfu.Len()
+		b1, b2 := c.dead.mru.Len(), c.dead.mfu.Len()
+		switch {
+		case t1+b1 > size:
+			t.Fatalf("step %d: |L1| = %d; want <= %d", i, t1+b1, size)
+		case t1+t2+b1+b2 > 2*size:
+			t.Fatalf("step %d: |L1|+|L2| = %d; want <= %d", i, t1+t2+b1+b2, 2*size)
+		case t1+t2 > size:
+			t.Fatalf("step %d: |T1|+|T2| = %d; want <= %d", i, t1+t2, size)
+		case b1+b2 > 0 && t1+t2 != size:
+			t.Fatalf("step %d: |T1|+|T2| = %d with ghosts; want %d", i, t1+t2, size)
+		case c.pivot < 0 || c.pivot > size:
+			t.Fatalf("step %d: p = %g; want in [0, %d]", i, c.pivot, size)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Arcsim replays access traces through an adaptive replacement cache
+// at one or more sizes and reports how it performs.
+//
+// Usage:
+//
+//	arcsim [-format=auto|arc|text] [-mrc=rate] -sizes=size,... trace...
+//	arcsim -workload=name [-keys=n] [-requests=n] [-seed=n] [-mrc=rate] -sizes=size,...
+//
+// Each access is a Get of its key, followed by a Set if the Get missed.
+// For each trace and size, arcsim prints the hit ratios of Belady's offline
+// optimal policy (OPT), which bounds what any policy can achieve, of LRU,
+// and of ARC, followed by ARC's number of ghost hits and final pivot.
+// Each trace is read into memory, because OPT depends on future accesses.
+//
+// With the -mrc flag, arcsim instead streams each trace through an estimator
+// of ARC's miss ratio curve that samples keys at the given rate, and prints the
+// estimated curve at the given sizes as CSV. See package mrc for details.
+//
+// See readTrace for a description of the supported trace formats.
+//
+// With the -workload flag, arcsim replays a synthetic workload instead of traces.
+// See newWorkload for a description of the supported workloads.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"bursavich.dev/arc"
+	"bursavich.dev/arc/mrc"
+	"bursavich.dev/arc/policy"
+	"bursavich.dev/arc/workload"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "arcsim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("arcsim", flag.ContinueOnError)
+	format := fs.String("format", formatAuto, "trace format: auto, arc, or text")
+	sizeList := fs.String("sizes", "", "comma-separated list of cache sizes")
+	mrcRate := fs.Float64("mrc", 0, "if positive, the sampling rate at which to estimate a miss ratio curve")
+	wlName := fs.String("workload", "", "synthetic workload: uniform, zipf, scan, loop, hotset, or scanhot")
+	wlKeys := fs.Uint64("keys", 100000, "number of distinct keys in the synthetic workload")
+	wlRequests := fs.Int("requests", 1000000, "number of requests in the synthetic workload")
+	wlSeed := fs.Int64("seed", 1, "random seed of the synthetic workload")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *mrcRate < 0 || *mrcRate > 1 {
+		return fmt.Errorf("invalid sampling rate: %v", *mrcRate)
+	}
+	sizes, err := parseSizes(*sizeList)
+	if err != nil {
+		return err
+	}
+	if *wlName != "" {
+		if fs.NArg() > 0 {
+			return errors.New("both a workload and trace files")
+		}
+		g, err := newWorkload(*wlName, *wlSeed, *wlKeys)
+		if err != nil {
+			return err
+		}
+		if *wlRequests <= 0 {
+			return fmt.Errorf("invalid number of requests: %d", *wlRequests)
+		}
+		keys := workload.Take(g, *wlRequests)
+		src := func(fn func(key uint64)) error {
+			for _, key := range keys {
+				fn(key)
+			}
+			return nil
+		}
+		if *mrcRate > 0 {
+			return estimate(w, src, sizes, *mrcRate)
+		}
+		return simulate(w, *wlName+" workload", src, sizes)
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no trace files")
+	}
+	for i, name := range fs.Args() {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		src := func(fn func(key uint64)) error {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := readTrace(f, *format, fn); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		}
+		if *mrcRate > 0 {
+			err = estimate(w, src, sizes, *mrcRate)
+		} else {
+			err = simulate(w, name, src, sizes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSizes parses a comma-separated list of cache sizes.
+func parseSizes(s string) ([]int, error) {
+	if s == "" {
+		return nil, errors.New("no cache sizes")
+	}
+	var sizes []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid cache size: %q", f)
+		}
+		sizes = append(sizes, n)
+	}
+	return sizes, nil
+}
+
+// simulate reads all of the keys from src and writes the hit ratios
+// of OPT, LRU, and ARC at each size to w, under the given label.
+func simulate(w io.Writer, label string, src func(fn func(key uint64)) error, sizes []int) error {
+	var keys []uint64
+	if err := src(func(key uint64) { keys = append(keys, key) }); err != nil {
+		return err
+	}
+	next := nextUses(keys)
+
+	fmt.Fprintf(w, "%s: %d requests\n", label, len(keys))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "size\tOPT\tLRU\tARC\tghost hits\tpivot\t")
+	for _, size := range sizes {
+		optHits := opt(keys, next, size)
+		lruHits := replay(policy.NewLRU[uint64, struct{}](size), keys)
+		c := arc.New[uint64, struct{}](size)
+		arcHits := replay(c, keys)
+		s := c.Stats()
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t\n", size,
+			ratio(optHits, len(keys)), ratio(lruHits, len(keys)), ratio(arcHits, len(keys)),
+			s.GhostHits(), s.Pivot,
+		)
+	}
+	return tw.Flush()
+}
+
+// estimate streams the keys from src through an estimator of ARC's
+// miss ratio curve and writes the curve to w as CSV.
+func estimate(w io.Writer, src func(fn func(key uint64)) error, sizes []int, rate float64) error {
+	e := mrc.New[uint64](sizes, rate)
+	if err := src(e.Access); err != nil {
+		return err
+	}
+	return e.Curve().WriteCSV(w)
+}
+
+// replay replays the keys through the cache and returns the number of hits.
+func replay(c arc.Policy[uint64, struct{}], keys []uint64) (hits uint64) {
+	for _, key := range keys {
+		if _, ok := c.Get(key); ok {
+			hits++
+		} else {
+			c.Set(key, struct{}{})
+		}
+	}
+	return hits
+}
+
+// ratio formats the hit ratio as a percentage.
+func ratio(hits uint64, requests int) string {
+	if requests == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", 100*float64(hits)/float64(requests))
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "trace.lis")
+	if err := os.WriteFile(name, []byte("0 4 0 0\n0 4 0 1\n0 4 0 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := run([]string{"-sizes=2,4", name}, &buf); err != nil {
+		t.Fatalf("run: unexpected error: %v", err)
+	}
+	want := name + ": 12 requests\n" +
+		"  size     OPT     LRU     ARC  ghost hits  pivot\n" +
+		"     2  25.00%   0.00%   8.33%           7      0\n" +
+		"     4  66.67%  66.67%  66.67%           0      2\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunMRC(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "trace.txt")
+	if err := os.WriteFile(name, []byte("a\nb\na\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := run([]string{"-mrc=1", "-sizes=1,2", name}, &buf); err != nil {
+		t.Fatalf("run: unexpected error: %v", err)
+	}
+	want := "size,miss_ratio,hit_ratio\n" +
+		"1,1.000000,0.000000\n" +
+		"2,0.500000,0.500000\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunWorkload(t *testing.T) {
+	for _, name := range []string{"uniform", "zipf", "scan", "loop", "hotset", "scanhot"} {
+		var buf bytes.Buffer
+		args := []string{"-workload=" + name, "-keys=100", "-requests=1000", "-sizes=10,50"}
+		if err := run(args, &buf); err != nil {
+			t.Fatalf("run(%q): unexpected error: %v", args, err)
+		}
+		if want := name + " workload: 1000 requests\n"; !strings.HasPrefix(buf.String(), want) {
+			t.Fatalf("run(%q): unexpected output:\n%s", args, buf.String())
+		}
+	}
+
+	// A loop that is larger than the cache never hits with LRU.
+	var buf bytes.Buffer
+	if err := run([]string{"-workload=loop", "-keys=100", "-requests=1000", "-sizes=50"}, &buf); err != nil {
+		t.Fatalf("run: unexpected error: %v", err)
+	}
+	if lines := strings.Split(buf.String(), "\n"); !strings.Contains(lines[2], "  0.00%") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"trace"}, err: "no cache sizes"},
+		{args: []string{"-sizes=10,0", "trace"}, err: "invalid cache size"},
+		{args: []string{"-sizes=10"}, err: "no trace files"},
+		{args: []string{"-sizes=10", "-mrc=2", "trace"}, err: "invalid sampling rate"},
+		{args: []string{"-sizes=10", "-workload=zipf", "trace"}, err: "both a workload and trace files"},
+		{args: []string{"-sizes=10", "-workload=pareto"}, err: "unknown workload"},
+		{args: []string{"-sizes=10", "-workload=zipf", "-keys=0"}, err: "invalid number of keys"},
+		{args: []string{"-sizes=10", "-workload=zipf", "-requests=0"}, err: "invalid number of requests"},
+		{args: []string{"-sizes=10", filepath.Join(t.TempDir(), "missing")}, err: "no such file"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := run(tt.args, &buf); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("run(%q): unexpected error: got: %v; want: %q", tt.args, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import "container/heap"
+
+// nextUses returns the index of the next access to the same key
+// for each access in the trace, or len(keys) if there is none.
+func nextUses(keys []uint64) []int {
+	next := make([]int, len(keys))
+	last := make(map[uint64]int)
+	for i := len(keys) - 1; i >= 0; i-- {
+		if j, ok := last[keys[i]]; ok {
+			next[i] = j
+		} else {
+			next[i] = len(keys)
+		}
+		last[keys[i]] = i
+	}
+	return next
+}
+
+// opt returns the number of hits of Belady's optimal replacement policy, MIN,
+// with a cache of the given size. When the cache is full, a miss evicts the key
+// whose next access is furthest in the future. Like the other policies, every
+// missed key is added to the cache.
+func opt(keys []uint64, next []int, size int) (hits uint64) {
+	var (
+		h   optHeap
+		tbl = make(map[uint64]*optEntry, size)
+	)
+	for i, key := range keys {
+		if e, ok := tbl[key]; ok {
+			hits++
+			e.next = next[i]
+			heap.Fix(&h, e.index)
+			continue
+		}
+		if len(h) >= size {
+			delete(tbl, heap.Pop(&h).(*optEntry).key)
+		}
+		e := &optEntry{key: key, next: next[i]}
+		tbl[key] = e
+		heap.Push(&h, e)
+	}
+	return hits
+}
+
+type optEntry struct {
+	key   uint64
+	next  int // index of the next access
+	index int // index in the heap
+}
+
+// optHeap is a max-heap of entries ordered by their next access.
+type optHeap []*optEntry
+
+func (h optHeap) Len() int           { return len(h) }
+func (h optHeap) Less(i, j int) bool { return h[i].next > h[j].next }
+
+func (h optHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *optHeap) Push(x any) {
+	e := x.(*optEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *optHeap) Pop() any {
+	old := *h
+	n := len(old) - 1
+	e := old[n]
+	old[n] = nil
+	*h = old[:n]
+	return e
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc"
+	"bursavich.dev/arc/policy"
+)
+
+// A reference string from the textbook treatment of page replacement,
+// where three frames incur 9 faults with OPT and 12 with LRU.
+var textbook = []uint64{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+
+func TestNextUses(t *testing.T) {
+	keys := []uint64{1, 2, 1, 3, 2, 1}
+	if got, want := nextUses(keys), []int{2, 4, 5, 6, 6, 6}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected next uses: got: %v; want: %v", got, want)
+	}
+}
+
+func TestOPT(t *testing.T) {
+	if got, want := opt(textbook, nextUses(textbook), 3), uint64(len(textbook)-9); got != want {
+		t.Fatalf("unexpected OPT hits: got: %d; want: %d", got, want)
+	}
+	if got, want := replay(policy.NewLRU[uint64, struct{}](3), textbook), uint64(len(textbook)-12); got != want {
+		t.Fatalf("unexpected LRU hits: got: %d; want: %d", got, want)
+	}
+}
+
+// TestOPTBound checks that no other policy beats OPT.
+func TestOPTBound(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	keys := make([]uint64, 10000)
+	for i := range keys {
+		keys[i] = uint64(rng.ExpFloat64() * 100)
+	}
+	next := nextUses(keys)
+	for _, size := range []int{1, 10, 50, 200} {
+		best := opt(keys, next, size)
+		for name, c := range map[string]arc.Policy[uint64, struct{}]{
+			"LRU": policy.NewLRU[uint64, struct{}](size),
+			"ARC": arc.New[uint64, struct{}](size),
+		} {
+			if hits := replay(c, keys); hits > best {
+				t.Errorf("size %d: %s hits %d exceed OPT hits %d", size, name, hits, best)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Trace formats.
+const (
+	formatAuto = "auto"
+	formatARC  = "arc"
+	formatText = "text"
+)
+
+// maxARCBlocks is the max number of blocks in a request of an ARC trace,
+// so a corrupt line can't make readTrace run practically forever.
+const maxARCBlocks = 1 << 20
+
+// readTrace reads the trace from r in the given format and calls fn with each key.
+//
+// In the ARC format, used by the traces in Megiddo and Modha's evaluations,
+// each line has four fields: a starting block, a number of blocks, a field
+// that is ignored, and a request number. Each line is a request for every
+// block from the starting block through the number of blocks that follow it.
+// The number of blocks must be between 1 and 2^20.
+//
+// In the text format, each line is a key. Text keys are mapped to integers
+// in the order in which they first appear.
+//
+// In the auto format, the format is detected from the first non-blank line.
+// Blank lines are ignored in all formats.
+func readTrace(r io.Reader, format string, fn func(key uint64)) error {
+	var (
+		sc   = bufio.NewScanner(r)
+		ids  map[string]uint64
+		line int
+	)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		if format == formatAuto {
+			format = detectFormat(s)
+		}
+		switch format {
+		case formatARC:
+			start, n, err := parseARC(s)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if n == 0 || n > maxARCBlocks {
+				return fmt.Errorf("line %d: invalid number of blocks: %d", line, n)
+			}
+			if start > math.MaxUint64-(n-1) {
+				return fmt.Errorf("line %d: blocks overflow: %d + %d", line, start, n)
+			}
+			for i := uint64(0); i < n; i++ {
+				fn(start + i)
+			}
+		case formatText:
+			if ids == nil {
+				ids = make(map[string]uint64)
+			}
+			id, ok := ids[s]
+			if !ok {
+				id = uint64(len(ids))
+				ids[s] = id
+			}
+			fn(id)
+		default:
+			return fmt.Errorf("unknown trace format: %q", format)
+		}
+	}
+	return sc.Err()
+}
+
+// detectFormat returns the format of a trace with the given first line.
+func detectFormat(line string) string {
+	if _, _, err := parseARC(line); err == nil {
+		return formatARC
+	}
+	return formatText
+}
+
+// parseARC parses a line of an ARC trace and returns its starting block and number of blocks.
+func parseARC(line string) (start, n uint64, err error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return 0, 0, fmt.Errorf("invalid ARC trace line: %q", line)
+	}
+	for _, f := range fields {
+		if _, err := strconv.ParseUint(f, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid ARC trace line: %q", line)
+		}
+	}
+	start, _ = strconv.ParseUint(fields[0], 10, 64)
+	n, _ = strconv.ParseUint(fields[1], 10, 64)
+	return start, n, nil
+}
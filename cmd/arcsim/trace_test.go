@@ -0,0 +1,90 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		trace  string
+		keys   []uint64
+		err    string // substring of the error, if any
+	}{
+		{
+			name:   "arc",
+			format: formatARC,
+			trace:  "10 3 0 0\n\n7 1 0 1\n11 2 0 2\n",
+			keys:   []uint64{10, 11, 12, 7, 11, 12},
+		},
+		{
+			name:   "text",
+			format: formatText,
+			trace:  "foo\nbar\n\nfoo\n10 3 0 0\nbar\n",
+			keys:   []uint64{0, 1, 0, 2, 1},
+		},
+		{
+			name:   "auto arc",
+			format: formatAuto,
+			trace:  "\n 5 2 0 0 \n",
+			keys:   []uint64{5, 6},
+		},
+		{
+			name:   "auto text",
+			format: formatAuto,
+			trace:  "/index.html\n/about.html\n/index.html\n",
+			keys:   []uint64{0, 1, 0},
+		},
+		{
+			name:   "invalid arc",
+			format: formatARC,
+			trace:  "10 3 0 0\nfoo\n",
+			keys:   []uint64{10, 11, 12},
+			err:    "line 2:",
+		},
+		{
+			name:   "zero blocks",
+			format: formatARC,
+			trace:  "10 3 0 0\n7 0 0 1\n",
+			keys:   []uint64{10, 11, 12},
+			err:    "line 2: invalid number of blocks",
+		},
+		{
+			name:   "too many blocks",
+			format: formatAuto,
+			trace:  "0 18446744073709551615 0 0\n",
+			err:    "line 1: invalid number of blocks",
+		},
+		{
+			name:   "blocks overflow",
+			format: formatARC,
+			trace:  "\n18446744073709551615 2 0 0\n",
+			err:    "line 2: blocks overflow",
+		},
+		{
+			name:   "invalid format",
+			format: "csv",
+			trace:  "foo\n",
+			err:    "unknown trace format",
+		},
+	}
+	for _, tt := range tests {
+		var keys []uint64
+		err := readTrace(strings.NewReader(tt.trace), tt.format, func(key uint64) {
+			keys = append(keys, key)
+		})
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: unexpected error: got: %v; want: %q", tt.name, err, tt.err)
+		}
+		if !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("%s: unexpected keys: got: %v; want: %v", tt.name, keys, tt.keys)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"bursavich.dev/arc/workload"
+)
+
+// newWorkload returns a generator of the named synthetic workload over the given number of keys:
+//
+//   - uniform: keys chosen uniformly at random
+//   - zipf: keys chosen with a Zipfian distribution with a skew of 0.99
+//   - scan: a sequential scan of keys that never repeat
+//   - loop: a sequential loop over the keys
+//   - hotset: keys chosen uniformly from a hot set of a tenth of the keys,
+//     which shifts after every ten accesses per hot key
+//   - scanhot: keys chosen with a Zipfian distribution, mixed evenly with a scan
+func newWorkload(name string, seed int64, keys uint64) (workload.Generator, error) {
+	if keys == 0 {
+		return nil, fmt.Errorf("invalid number of keys: %d", keys)
+	}
+	switch name {
+	case "uniform":
+		return workload.Uniform(seed, keys), nil
+	case "zipf":
+		return workload.Zipf(seed, keys, 0.99), nil
+	case "scan":
+		return workload.Scan(0), nil
+	case "loop":
+		return workload.Loop(keys), nil
+	case "hotset":
+		hot := max(1, keys/10)
+		return workload.HotSet(seed, keys, hot, int(10*hot)), nil
+	case "scanhot":
+		return workload.ScanHot(seed, keys, 0.5), nil
+	default:
+		return nil, fmt.Errorf("unknown workload: %q", name)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import "strconv"
+
+// EvictCause is the reason a value was removed from a cache.
+type EvictCause int
+
+const (
+	// EvictCapacity means the value was evicted to make room for another.
+	EvictCapacity EvictCause = iota + 1
+	// EvictDeleted means the value was deleted.
+	EvictDeleted
+	// EvictReplaced means the value was replaced by another value for the same key.
+	EvictReplaced
+	// EvictExpired means the value's time-to-live elapsed.
+	EvictExpired
+	// EvictCleared means the value was removed because the cache was cleared.
+	EvictCleared
+	// EvictRejected means the value was never cached because it's heavier than the
+	// cache's max total weight. It isn't counted as an eviction in the cache's Stats.
+	EvictRejected
+)
+
+func (c EvictCause) String() string {
+	switch c {
+	case EvictCapacity:
+		return "capacity"
+	case EvictDeleted:
+		return "deleted"
+	case EvictReplaced:
+		return "replaced"
+	case EvictExpired:
+		return "expired"
+	case EvictCleared:
+		return "cleared"
+	case EvictRejected:
+		return "rejected"
+	}
+	return "EvictCause(" + strconv.Itoa(int(c)) + ")"
+}
+
+// An EvictFunc is called with the key and value of an item removed from a cache
+// and the cause of its removal. It is called synchronously by the operation that
+// removed the item and it must not access the cache.
+type EvictFunc[K comparable, V any] func(key K, value V, cause EvictCause)
+
+// WithEvictFunc returns an Option that sets a function to be called
+// whenever a value is removed from the cache. The function's key and
+// value types must match those of the cache.
+func WithEvictFunc[K comparable, V any](fn EvictFunc[K, V]) Option {
+	return optionFunc(func(o *options) {
+		o.evictFunc = fn
+	})
+}
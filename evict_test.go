@@ -0,0 +1,77 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"bursavich.dev/arc/arctest"
+)
+
+func TestEvictFunc(t *testing.T) {
+	type event struct {
+		key   int
+		val   string
+		cause EvictCause
+	}
+	var events []event
+	clock := arctest.NewClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New[int, string](2, WithClock(clock), WithEvictFunc(func(key int, val string, cause EvictCause) {
+		events = append(events, event{key, val, cause})
+	}))
+	tests := []struct {
+		op   func()
+		want []event
+	}{
+		{op: func() { c.Set(0, "a") }},
+		{op: func() { c.Set(1, "b") }},
+		{op: func() { c.Set(2, "c") }, want: []event{{0, "a", EvictCapacity}}},
+		{op: func() { c.Set(1, "d") }, want: []event{{1, "b", EvictReplaced}}},
+		{op: func() { c.Delete(1) }, want: []event{{1, "d", EvictDeleted}}},
+		{op: func() { c.Delete(0) }},
+		{op: func() { c.SetWithTTL(3, "e", time.Second) }},
+		{op: func() { clock.Advance(time.Second) }},
+		{op: func() { c.Get(3) }, want: []event{{3, "e", EvictExpired}}},
+		{op: func() { c.SetWithTTL(4, "f", time.Second) }},
+		{op: func() { clock.Advance(time.Second) }},
+		{op: func() { c.Set(5, "g") }, want: []event{{2, "c", EvictCapacity}}},
+		{op: func() { c.Set(6, "h") }, want: []event{{4, "f", EvictExpired}}},
+	}
+	for i, tt := range tests {
+		events = nil
+		tt.op()
+		if !reflect.DeepEqual(events, tt.want) {
+			t.Fatalf("step %d: unexpected events; got: %v; want: %v", i, events, tt.want)
+		}
+	}
+}
+
+func TestEvictFuncTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	New[int, string](1, WithEvictFunc(func(key string, val int, cause EvictCause) {}))
+}
+
+func TestEvictCauseString(t *testing.T) {
+	for cause, want := range map[EvictCause]string{
+		EvictCapacity: "capacity",
+		EvictDeleted:  "deleted",
+		EvictReplaced: "replaced",
+		EvictExpired:  "expired",
+		EvictCleared:  "cleared",
+		EvictRejected: "rejected",
+		0:             "EvictCause(0)",
+	} {
+		if got := fmt.Sprint(cause); got != want {
+			t.Errorf("unexpected string; got: %q; want: %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import "strconv"
+
+// Segment identifies the most-recently-used or most-frequently-used part of a cache.
+type Segment int
+
+const (
+	// MRU holds keys that have been used once recently.
+	MRU Segment = iota
+	// MFU holds keys that have been used more than once recently.
+	MFU
+)
+
+// checkSegment panics if seg is not MRU or MFU.
+func checkSegment(seg Segment) {
+	if seg != MRU && seg != MFU {
+		panic("arc: invalid Segment")
+	}
+}
+
+func (s Segment) String() string {
+	switch s {
+	case MRU:
+		return "mru"
+	case MFU:
+		return "mfu"
+	}
+	return "Segment(" + strconv.Itoa(int(s)) + ")"
+}
+
+// GhostHooks are functions called when the dead (ghost) lists of a cache change.
+// They are called synchronously by the operation that changed the lists and they
+// must not access the cache. Any of them may be nil.
+type GhostHooks[K comparable] struct {
+	// Add is called when a key's value is evicted and the key enters the segment's dead list.
+	Add func(key K, seg Segment)
+	// Drop is called when a key is dropped from the segment's dead list,
+	// either to make room or because it was deleted.
+	Drop func(key K, seg Segment)
+	// Hit is called when a key in the segment's dead list is set, reviving
+	// the key and moving the pivot from oldPivot to newPivot.
+	Hit func(key K, seg Segment, oldPivot, newPivot int64)
+}
+
+// WithGhostHooks returns an Option that sets functions to be called whenever
+// the cache's dead lists change. The hooks' key type must match that of the cache.
+func WithGhostHooks[K comparable](hooks GhostHooks[K]) Option {
+	return optionFunc(func(o *options) {
+		o.ghostHooks = hooks
+	})
+}
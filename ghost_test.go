@@ -0,0 +1,58 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGhostHooks(t *testing.T) {
+	var events []string
+	c := New[int, string](2, WithGhostHooks(GhostHooks[int]{
+		Add: func(key int, seg Segment) {
+			events = append(events, fmt.Sprintf("add %d %v", key, seg))
+		},
+		Drop: func(key int, seg Segment) {
+			events = append(events, fmt.Sprintf("drop %d %v", key, seg))
+		},
+		Hit: func(key int, seg Segment, oldPivot, newPivot int64) {
+			events = append(events, fmt.Sprintf("hit %d %v %d->%d", key, seg, oldPivot, newPivot))
+		},
+	}))
+	tests := []struct {
+		cmd   string
+		key   int
+		want  []string
+		state state[int]
+	}{
+		{cmd: "set", key: 0, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, want: []string{"add 0 mru"}, state: state[int]{{0}, {1, 2}, {}, {}}},
+		{cmd: "set", key: 3, want: []string{"add 1 mru"}, state: state[int]{{0, 1}, {2, 3}, {}, {}}},
+		{cmd: "set", key: 4, want: []string{"add 2 mru", "drop 0 mru"}, state: state[int]{{1, 2}, {3, 4}, {}, {}}},
+		{cmd: "set", key: 1, want: []string{"hit 1 mru 1->2", "add 3 mru"}, state: state[int]{{2, 3}, {4}, {1}, {}}},
+		{cmd: "set", key: 5, want: []string{"add 1 mfu", "drop 2 mru"}, state: state[int]{{3}, {4, 5}, {}, {1}}},
+		{cmd: "set", key: 1, want: []string{"hit 1 mfu 2->1", "add 4 mru"}, state: state[int]{{3, 4}, {5}, {1}, {}}},
+		{cmd: "del", key: 3, want: []string{"drop 3 mru"}, state: state[int]{{4}, {5}, {1}, {}}},
+		{cmd: "del", key: 5, state: state[int]{{4}, {}, {1}, {}}},
+	}
+	for i, tt := range tests {
+		events = nil
+		switch tt.cmd {
+		case "set":
+			c.Set(tt.key, fmt.Sprint(tt.key))
+		case "del":
+			c.Delete(tt.key)
+		}
+		if !reflect.DeepEqual(events, tt.want) {
+			t.Fatalf("step %d: %s %d: unexpected events; got: %q; want: %q", i, tt.cmd, tt.key, events, tt.want)
+		}
+		if got := cacheState(c); !reflect.DeepEqual(got, tt.state) {
+			t.Fatalf("step %d: %s %d: unexpected state; got: %v; want: %v", i, tt.cmd, tt.key, got, tt.state)
+		}
+	}
+}
@@ -1,3 +1,3 @@
 module bursavich.dev/arc
 
-go 1.18
+go 1.24
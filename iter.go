@@ -0,0 +1,162 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import "iter"
+
+// All returns an iterator over the cache's live keys and values:
+// first the MRU segment, then the MFU segment. See Live for details.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if rangeLive(c, liveMRU, yield) {
+			rangeLive(c, liveMFU, yield)
+		}
+	}
+}
+
+// Live returns an iterator over the live keys and values in the segment,
+// from most to least recently used. Expired values are skipped.
+// Iteration doesn't update recency or frequency.
+//
+// The current key may be deleted during iteration. If the cache is
+// otherwise modified during iteration, keys may be skipped or visited
+// more than once.
+//
+// It panics if seg is not MRU or MFU.
+func (c *Cache[K, V]) Live(seg Segment) iter.Seq2[K, V] {
+	l := liveList(seg)
+	return func(yield func(K, V) bool) {
+		rangeLive(c, l, yield)
+	}
+}
+
+// Ghosts returns an iterator over the keys in the segment's dead list,
+// from most to least recently evicted. It has the same behavior as Live
+// if the cache is modified during iteration.
+//
+// It panics if seg is not MRU or MFU.
+func (c *Cache[K, V]) Ghosts(seg Segment) iter.Seq[K] {
+	l := deadList(seg)
+	return func(yield func(K) bool) {
+		s := &c.items
+		for i := s.front(l); i != l && s.slots[i].list == l; {
+			next := s.slots[i].next
+			if !yield(s.slots[i].key) {
+				return
+			}
+			i = next
+		}
+	}
+}
+
+// Range calls fn for each live key and value in the cache, in the order of All,
+// until fn returns false.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.All()(fn)
+}
+
+// rangeLive yields the unexpired items in l and reports whether iteration should continue.
+func rangeLive[K comparable, V any](c *Cache[K, V], l int32, yield func(K, V) bool) bool {
+	s := &c.items
+	// Slots are reused, so stop if the next one has moved to another list.
+	for i := s.front(l); i != l && s.slots[i].list == l; {
+		// Find the next slot before yielding so that the current one may be deleted.
+		next := s.slots[i].next
+		if it := s.slots[i]; !c.expired(it.exp) && !yield(it.key, it.val) {
+			return false
+		}
+		i = next
+	}
+	return true
+}
+
+// All returns an iterator over the cache's live keys and values:
+// first the MRU segment, then the MFU segment. Expired values are skipped.
+// Iteration doesn't update recency or frequency.
+//
+// The iterator yields a snapshot of the cache taken when iteration begins,
+// so the cache may be used during iteration.
+func (c *SyncCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		rangeItems(c.snapshot(MRU, MFU), yield)
+	}
+}
+
+// Live returns an iterator over the live keys and values in the segment,
+// from most to least recently used. It has the same behavior as All.
+// It panics if seg is not MRU or MFU.
+func (c *SyncCache[K, V]) Live(seg Segment) iter.Seq2[K, V] {
+	checkSegment(seg)
+	return func(yield func(K, V) bool) {
+		rangeItems(c.snapshot(seg), yield)
+	}
+}
+
+// Ghosts returns an iterator over the keys in the segment's dead list,
+// from most to least recently evicted. The iterator yields a snapshot
+// of the list taken when iteration begins. It panics if seg is not MRU or MFU.
+func (c *SyncCache[K, V]) Ghosts(seg Segment) iter.Seq[K] {
+	checkSegment(seg)
+	return func(yield func(K) bool) {
+		c.mu.Lock()
+		var keys []K
+		for key := range c.c.Ghosts(seg) {
+			keys = append(keys, key)
+		}
+		c.mu.Unlock()
+		for _, key := range keys {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Range calls fn for each live key and value in the cache, in the order of All,
+// until fn returns false.
+func (c *SyncCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.All()(fn)
+}
+
+func (c *SyncCache[K, V]) snapshot(segs ...Segment) []item[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var items []item[K, V]
+	for _, seg := range segs {
+		for key, val := range c.c.Live(seg) {
+			items = append(items, item[K, V]{key: key, val: val})
+		}
+	}
+	return items
+}
+
+func rangeItems[K comparable, V any](items []item[K, V], yield func(K, V) bool) {
+	for _, it := range items {
+		if !yield(it.key, it.val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the cache's live keys and values,
+// shard by shard. Within each shard it has the same order and behavior
+// as SyncCache.All.
+func (c *ShardedCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range c.shards {
+			for key, val := range s.All() {
+				if !yield(key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Range calls fn for each live key and value in the cache, in the order of All,
+// until fn returns false.
+func (c *ShardedCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.All()(fn)
+}
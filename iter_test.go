@@ -0,0 +1,146 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"bursavich.dev/arc/arctest"
+)
+
+// newIterCache returns a cache in state [ 0 ... 1, 4, 5 | 3 ... 2 ]
+// where 5 is expired.
+func newIterCache(t *testing.T) (*Cache[int, string], *arctest.Clock) {
+	clock := arctest.NewClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New[int, string](4, WithClock(clock))
+	for i := 0; i < 4; i++ {
+		c.Set(i, strconv.Itoa(i))
+	}
+	c.Get(2)
+	c.Get(3)
+	c.Set(4, "4")
+	c.SetWithTTL(5, "5", time.Second)
+	clock.Advance(time.Second)
+	if got, want := cacheState(c), (state[int]{{0}, {1, 4, 5}, {3}, {2}}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	return c, clock
+}
+
+func collect[K comparable, V any](seq func(func(K, V) bool)) (keys []K, vals []V) {
+	for k, v := range seq {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	return keys, vals
+}
+
+func TestIter(t *testing.T) {
+	c, _ := newIterCache(t)
+	want := cacheState(c)
+
+	tests := []struct {
+		name string
+		seq  func(func(int, string) bool)
+		keys []int
+		vals []string
+	}{
+		{name: "All", seq: c.All(), keys: []int{4, 1, 3}, vals: []string{"4", "1", "3"}},
+		{name: "Live(MRU)", seq: c.Live(MRU), keys: []int{4, 1}, vals: []string{"4", "1"}},
+		{name: "Live(MFU)", seq: c.Live(MFU), keys: []int{3}, vals: []string{"3"}},
+		{name: "Range", seq: c.Range, keys: []int{4, 1, 3}, vals: []string{"4", "1", "3"}},
+	}
+	for _, tt := range tests {
+		keys, vals := collect(tt.seq)
+		if !reflect.DeepEqual(keys, tt.keys) || !reflect.DeepEqual(vals, tt.vals) {
+			t.Errorf("%s: got: (%v, %q); want: (%v, %q)", tt.name, keys, vals, tt.keys, tt.vals)
+		}
+	}
+	if got, want := slices.Collect(c.Ghosts(MRU)), []int{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ghosts(MRU): got: %v; want: %v", got, want)
+	}
+	if got, want := slices.Collect(c.Ghosts(MFU)), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ghosts(MFU): got: %v; want: %v", got, want)
+	}
+	if got := cacheState(c); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+}
+
+func TestIterBreak(t *testing.T) {
+	c, _ := newIterCache(t)
+	var keys []int
+	for key := range c.All() {
+		keys = append(keys, key)
+		if key == 1 {
+			break
+		}
+	}
+	if want := []int{4, 1}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", keys, want)
+	}
+}
+
+func TestIterDelete(t *testing.T) {
+	c, _ := newIterCache(t)
+	var keys []int
+	for key := range c.All() {
+		keys = append(keys, key)
+		c.Delete(key)
+	}
+	if want := []int{4, 1, 3}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", keys, want)
+	}
+	if got, want := cacheState(c), (state[int]{{0}, {5}, {}, {2}}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+}
+
+func TestSyncIter(t *testing.T) {
+	c, _ := newIterCache(t)
+	sc := &SyncCache[int, string]{c: c}
+	keys, _ := collect(sc.All())
+	if want := []int{4, 1, 3}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", keys, want)
+	}
+	// The cache may be used during iteration.
+	keys = nil
+	for key := range sc.Live(MFU) {
+		keys = append(keys, key)
+		sc.Set(key+10, "")
+	}
+	if want := []int{3}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", keys, want)
+	}
+	if got, want := slices.Collect(sc.Ghosts(MRU)), []int{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ghosts(MRU): got: %v; want: %v", got, want)
+	}
+}
+
+func TestIterInvalidSegment(t *testing.T) {
+	c := New[int, string](4)
+	s := NewSync[int, string](4)
+	for _, seg := range []Segment{-1, 2, 4} {
+		for name, fn := range map[string]func(){
+			"Cache.Live":       func() { c.Live(seg) },
+			"Cache.Ghosts":     func() { c.Ghosts(seg) },
+			"SyncCache.Live":   func() { s.Live(seg) },
+			"SyncCache.Ghosts": func() { s.Ghosts(seg) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s(%v): expected panic", name, seg)
+					}
+				}()
+				fn()
+			}()
+		}
+	}
+}
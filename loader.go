@@ -0,0 +1,108 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"context"
+	"errors"
+)
+
+var errLoaderPanic = errors.New("arc: loader panicked")
+
+// A Loader loads values for keys that are missing from a cache.
+type Loader[K comparable, V any] interface {
+	Load(ctx context.Context, key K) (V, error)
+}
+
+// LoaderFunc is an adapter to allow the use of ordinary functions as Loaders.
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Load returns fn(ctx, key).
+func (fn LoaderFunc[K, V]) Load(ctx context.Context, key K) (V, error) {
+	return fn(ctx, key)
+}
+
+// call is an in-flight or completed Loader call.
+type call[V any] struct {
+	done  chan struct{}
+	val   V
+	err   error
+	panic any // value recovered from a panicking Load, if any
+}
+
+// GetOrLoad reads the key's value from the cache. If the key is not found,
+// it loads the value with the loader and writes it to the cache.
+//
+// Concurrent calls for the same key share a single in-flight load and
+// all of them receive its result. A caller whose context is done before
+// the load completes returns the context's error, but the load continues
+// on behalf of the others. The load isn't canceled with the context of the
+// caller that started it. If the key is set or deleted while its value
+// is being loaded, the loaded value is returned but not written to the cache.
+//
+// If the loader panics, the callers receive an error, except for the caller
+// that started the load, which panics with the same value if it's still waiting.
+func (c *SyncCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	c.mu.Lock()
+	if val, ok := c.c.Get(key); ok {
+		c.mu.Unlock()
+		return val, nil
+	}
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call[V]{done: make(chan struct{})}
+		if c.calls == nil {
+			c.calls = make(map[K]*call[V])
+		}
+		c.calls[key] = cl
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		go c.load(context.WithoutCancel(ctx), key, cl, loader)
+	}
+	select {
+	case <-cl.done:
+		if !ok && cl.panic != nil {
+			panic(cl.panic)
+		}
+		return cl.val, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (c *SyncCache[K, V]) load(ctx context.Context, key K, cl *call[V], loader Loader[K, V]) {
+	defer func() {
+		cl.panic = recover()
+		c.mu.Lock()
+		if c.calls[key] == cl {
+			delete(c.calls, key)
+			if cl.err == nil {
+				c.c.Set(key, cl.val)
+			}
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	// If Load panics, the callers receive errLoaderPanic and the panic is recovered
+	// so that the caller that started the load may propagate it.
+	cl.err = errLoaderPanic
+	cl.val, cl.err = loader.Load(ctx, key)
+}
+
+// forget detaches any in-flight load of the key from the cache.
+// The caller must hold c.mu.
+func (c *SyncCache[K, V]) forget(key K) {
+	delete(c.calls, key)
+}
+
+// GetOrLoad reads the key's value from the cache. If the key is not found,
+// it loads the value with the loader and writes it to the cache.
+// See SyncCache.GetOrLoad for details.
+func (c *ShardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	return c.shard(key).GetOrLoad(ctx, key, loader)
+}
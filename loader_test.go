@@ -0,0 +1,229 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingLoader returns a loader that counts its calls, signals started
+// when a call begins, and waits for release before returning.
+func blockingLoader(loads *int32, started chan<- struct{}, release <-chan struct{}, err error) Loader[int, string] {
+	return LoaderFunc[int, string](func(ctx context.Context, key int) (string, error) {
+		atomic.AddInt32(loads, 1)
+		if started != nil {
+			started <- struct{}{}
+		}
+		<-release
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(key), nil
+	})
+}
+
+func TestGetOrLoadDedup(t *testing.T) {
+	const goroutines = 16
+	var (
+		c       = NewSync[int, string](4)
+		loads   int32
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+		loader  = blockingLoader(&loads, started, release, nil)
+		wg      sync.WaitGroup
+	)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || val != "1" {
+				t.Errorf("GetOrLoad(1): got: (%q, %v); want: (%q, <nil>)", val, err, "1")
+			}
+		}()
+	}
+	<-started
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Fatalf("unexpected number of loads: got: %d; want: 1", loads)
+	}
+	if val, ok := c.Get(1); !ok || val != "1" {
+		t.Fatalf("Get(1): got: (%q, %v); want: (%q, true)", val, ok, "1")
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	const goroutines = 16
+	var (
+		c       = NewSync[int, string](4)
+		loads   int32
+		release = make(chan struct{})
+		wantErr = errors.New("load failed")
+		loader  = blockingLoader(&loads, nil, release, wantErr)
+		wg      sync.WaitGroup
+	)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetOrLoad(context.Background(), 1, loader); err != wantErr {
+				t.Errorf("GetOrLoad(1): unexpected error: got: %v; want: %v", err, wantErr)
+			}
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1): unexpected value after failed load")
+	}
+}
+
+func TestGetOrLoadCanceled(t *testing.T) {
+	var (
+		c       = NewSync[int, string](4)
+		loads   int32
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+		loader  = blockingLoader(&loads, started, release, nil)
+		done    = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || val != "1" {
+			t.Errorf("GetOrLoad(1): got: (%q, %v); want: (%q, <nil>)", val, err, "1")
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetOrLoad(ctx, 1, loader); err != context.Canceled {
+		t.Fatalf("GetOrLoad(1): unexpected error: got: %v; want: %v", err, context.Canceled)
+	}
+	close(release)
+	<-done
+
+	if loads != 1 {
+		t.Fatalf("unexpected number of loads: got: %d; want: 1", loads)
+	}
+}
+
+func TestGetOrLoadFirstCanceled(t *testing.T) {
+	var (
+		c       = NewSync[int, string](4)
+		started = make(chan struct{})
+		release = make(chan struct{})
+		loadErr = make(chan error, 1)
+		done    = make(chan struct{})
+	)
+	loader := LoaderFunc[int, string](func(ctx context.Context, key int) (string, error) {
+		close(started)
+		<-release
+		loadErr <- ctx.Err()
+		return strconv.Itoa(key), nil
+	})
+
+	// The caller that starts the load is canceled while it's in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		if _, err := c.GetOrLoad(ctx, 1, loader); err != context.Canceled {
+			t.Errorf("GetOrLoad(1): unexpected error: got: %v; want: %v", err, context.Canceled)
+		}
+	}()
+	<-started
+	waiter := make(chan struct{})
+	go func() {
+		defer close(waiter)
+		if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || val != "1" {
+			t.Errorf("GetOrLoad(1): got: (%q, %v); want: (%q, <nil>)", val, err, "1")
+		}
+	}()
+	cancel()
+	<-done // returns without waiting for the load
+
+	close(release)
+	<-waiter
+	if err := <-loadErr; err != nil {
+		t.Fatalf("Load: unexpected context error: %v", err)
+	}
+	if val, ok := c.Get(1); !ok || val != "1" {
+		t.Fatalf("Get(1): got: (%q, %v); want: (%q, true)", val, ok, "1")
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	c := NewSync[int, string](4)
+	loader := LoaderFunc[int, string](func(ctx context.Context, key int) (string, error) {
+		panic("boom")
+	})
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("GetOrLoad(1): unexpected panic: got: %v; want: boom", r)
+			}
+		}()
+		c.GetOrLoad(context.Background(), 1, loader)
+	}()
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1): unexpected value after panicking load")
+	}
+}
+
+func TestGetOrLoadDeleted(t *testing.T) {
+	var (
+		c       = NewSync[int, string](4)
+		loads   int32
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+		loader  = blockingLoader(&loads, started, release, nil)
+		done    = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		if val, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || val != "1" {
+			t.Errorf("GetOrLoad(1): got: (%q, %v); want: (%q, <nil>)", val, err, "1")
+		}
+	}()
+	<-started
+	c.Delete(1)
+	close(release)
+	<-done
+
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1): unexpected value after delete during load")
+	}
+}
+
+func TestGetOrLoadGhostHit(t *testing.T) {
+	c := NewSync[int, string](2)
+	c.Set(0, "0")
+	c.Set(1, "1")
+	c.Set(2, "2")
+	if got, want := cacheState(c.c), (state[int]{{0}, {1, 2}, {}, {}}); got.String() != want.String() {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	pivot := c.c.pivot
+
+	loader := LoaderFunc[int, string](func(ctx context.Context, key int) (string, error) {
+		return strconv.Itoa(key), nil
+	})
+	if val, err := c.GetOrLoad(context.Background(), 0, loader); err != nil || val != "0" {
+		t.Fatalf("GetOrLoad(0): got: (%q, %v); want: (%q, <nil>)", val, err, "0")
+	}
+	if got, want := cacheState(c.c), (state[int]{{1}, {2}, {0}, {}}); got.String() != want.String() {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	if c.c.pivot != pivot+1 {
+		t.Fatalf("unexpected pivot: got: %d; want: %d", c.c.pivot, pivot+1)
+	}
+}
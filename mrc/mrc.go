@@ -0,0 +1,169 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Package mrc estimates miss ratio curves, which describe how a cache's
+// miss ratio falls as its size grows, to help choose the size of a cache.
+//
+// An Estimator samples accesses by the hash of their keys, in the manner of
+// SHARDS, and replays the sampled accesses through miniature simulations of
+// the cache at each size, scaled down by the sampling rate. Because every
+// access to a sampled key is seen, each miniature cache sees a representative
+// slice of the workload, and its miss ratio estimates that of the full-size cache.
+// This works for policies like ARC that, unlike LRU, are not stack algorithms.
+//
+// See:
+//
+//	https://www.usenix.org/conference/fast15/technical-sessions/presentation/waldspurger
+//	https://www.usenix.org/conference/atc17/technical-sessions/presentation/waldspurger
+package mrc
+
+import (
+	"encoding/csv"
+	"hash/maphash"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"bursavich.dev/arc"
+)
+
+// modulus is the range of the hash values compared against the sampling threshold.
+const modulus = 1 << 24
+
+// A Point is the estimated miss ratio of a cache of a given size.
+type Point struct {
+	Size      int
+	MissRatio float64
+}
+
+// HitRatio returns the estimated hit ratio.
+func (p Point) HitRatio() float64 {
+	return 1 - p.MissRatio
+}
+
+// A Curve is a miss ratio curve.
+type Curve []Point
+
+// WriteCSV writes the curve to w as CSV with a header
+// and columns for size, miss ratio, and hit ratio.
+func (c Curve) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"size", "miss_ratio", "hit_ratio"})
+	for _, p := range c {
+		cw.Write([]string{
+			strconv.Itoa(p.Size),
+			strconv.FormatFloat(p.MissRatio, 'f', 6, 64),
+			strconv.FormatFloat(p.HitRatio(), 'f', 6, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Estimator estimates the miss ratio curve of a cache from a stream of accesses.
+// It is safe for concurrent access, so it may sample live traffic.
+type Estimator[K comparable] struct {
+	seed      maphash.Seed
+	threshold uint64
+	accesses  atomic.Uint64
+
+	mu      sync.Mutex
+	sizes   []int
+	caches  []arc.Policy[K, struct{}]
+	hits    []uint64
+	sampled uint64
+}
+
+// New returns an Estimator of the miss ratio curve of an arc.Cache at each of
+// the given sizes, which sample keys at the given rate, between 0 and 1.
+//
+// Lower rates use less time and memory, but give less accurate estimates,
+// especially for sizes that are small relative to the inverse of the rate.
+func New[K comparable](sizes []int, rate float64) *Estimator[K] {
+	return NewFunc(sizes, rate, func(size int) arc.Policy[K, struct{}] {
+		return arc.New[K, struct{}](size)
+	})
+}
+
+// NewFunc is like New, but it estimates the miss ratio curve of caches created
+// by newCache. The sizes passed to newCache are scaled by the sampling rate.
+func NewFunc[K comparable](sizes []int, rate float64, newCache func(size int) arc.Policy[K, struct{}]) *Estimator[K] {
+	if !(rate > 0 && rate <= 1) {
+		panic("mrc: rate must be greater than 0 and not greater than 1")
+	}
+	e := &Estimator[K]{
+		seed:      maphash.MakeSeed(),
+		threshold: uint64(math.Round(rate * modulus)),
+		sizes:     make([]int, len(sizes)),
+		caches:    make([]arc.Policy[K, struct{}], len(sizes)),
+		hits:      make([]uint64, len(sizes)),
+	}
+	if e.threshold == 0 {
+		e.threshold = 1
+	}
+	copy(e.sizes, sizes)
+	for i, size := range sizes {
+		if size <= 0 {
+			panic("mrc: size must be greater than 0")
+		}
+		e.caches[i] = newCache(max(1, int(math.Round(float64(size)*rate))))
+	}
+	return e
+}
+
+// Access records an access of the key.
+func (e *Estimator[K]) Access(key K) {
+	e.accesses.Add(1)
+	if maphash.Comparable(e.seed, key)%modulus >= e.threshold {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.sampled++
+	for i, c := range e.caches {
+		if _, ok := c.Get(key); ok {
+			e.hits[i]++
+		} else {
+			c.Set(key, struct{}{})
+		}
+	}
+}
+
+// Curve returns the estimated miss ratio curve, with a point for each size
+// in the order given. If no accesses have been sampled, every estimated
+// miss ratio is 1.
+//
+// A few popular keys may account for many accesses, so the number of sampled
+// accesses can stray far from its expected value. As in SHARDS, the misses are
+// divided by the expected number rather than the actual number, which corrects
+// for the accesses of popular keys that were or weren't sampled.
+func (e *Estimator[K]) Curve() Curve {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	expected := float64(e.accesses.Load()) * float64(e.threshold) / modulus
+	curve := make(Curve, len(e.sizes))
+	for i, size := range e.sizes {
+		curve[i] = Point{Size: size, MissRatio: 1}
+		if e.sampled > 0 {
+			misses := float64(e.sampled - e.hits[i])
+			curve[i].MissRatio = min(1, misses/expected)
+		}
+	}
+	return curve
+}
+
+// Sizes returns up to n distinct sizes evenly spaced from maxSize/n to maxSize.
+func Sizes(maxSize, n int) []int {
+	sizes := make([]int, 0, n)
+	for i := 1; i <= n; i++ {
+		if size := maxSize * i / n; size > 0 && (len(sizes) == 0 || size > sizes[len(sizes)-1]) {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
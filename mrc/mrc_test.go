@@ -0,0 +1,114 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package mrc
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc"
+)
+
+// trace returns a skewed trace of n accesses to keys.
+func trace(n, keys int) []int {
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.1, 10, uint64(keys-1))
+	t := make([]int, n)
+	for i := range t {
+		t[i] = int(zipf.Uint64())
+	}
+	return t
+}
+
+// missRatio returns the exact miss ratio of an arc.Cache of the given size.
+func missRatio(trace []int, size int) float64 {
+	c := arc.New[int, struct{}](size)
+	var misses int
+	for _, key := range trace {
+		if _, ok := c.Get(key); !ok {
+			misses++
+			c.Set(key, struct{}{})
+		}
+	}
+	return float64(misses) / float64(len(trace))
+}
+
+func TestEstimatorExact(t *testing.T) {
+	var (
+		tr    = trace(20000, 5000)
+		sizes = []int{10, 100, 1000}
+		e     = New[int](sizes, 1)
+	)
+	for _, key := range tr {
+		e.Access(key)
+	}
+	for _, p := range e.Curve() {
+		if want := missRatio(tr, p.Size); p.MissRatio != want {
+			t.Errorf("size %d: unexpected miss ratio: got: %f; want: %f", p.Size, p.MissRatio, want)
+		}
+	}
+}
+
+func TestEstimatorSampled(t *testing.T) {
+	var (
+		tr    = trace(200000, 50000)
+		sizes = Sizes(10000, 5)
+		e     = New[int](sizes, 0.1)
+	)
+	for _, key := range tr {
+		e.Access(key)
+	}
+	for _, p := range e.Curve() {
+		if want := missRatio(tr, p.Size); math.Abs(p.MissRatio-want) > 0.05 {
+			t.Errorf("size %d: unexpected miss ratio: got: %f; want: %f ± 0.05", p.Size, p.MissRatio, want)
+		}
+	}
+}
+
+func TestCurve(t *testing.T) {
+	e := New[string]([]int{1, 2}, 1)
+	for _, p := range e.Curve() {
+		if p.MissRatio != 1 {
+			t.Fatalf("size %d: unexpected miss ratio without accesses: got: %f; want: 1", p.Size, p.MissRatio)
+		}
+	}
+	for _, key := range []string{"a", "b", "a", "b"} {
+		e.Access(key)
+	}
+	curve := e.Curve()
+	if want := (Curve{{Size: 1, MissRatio: 1}, {Size: 2, MissRatio: 0.5}}); !reflect.DeepEqual(curve, want) {
+		t.Fatalf("unexpected curve: got: %v; want: %v", curve, want)
+	}
+
+	var buf bytes.Buffer
+	if err := curve.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: unexpected error: %v", err)
+	}
+	want := "size,miss_ratio,hit_ratio\n" +
+		"1,1.000000,0.000000\n" +
+		"2,0.500000,0.500000\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected CSV:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSizes(t *testing.T) {
+	tests := []struct {
+		max, n int
+		sizes  []int
+	}{
+		{max: 100, n: 4, sizes: []int{25, 50, 75, 100}},
+		{max: 10, n: 3, sizes: []int{3, 6, 10}},
+		{max: 2, n: 4, sizes: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		if got := Sizes(tt.max, tt.n); !reflect.DeepEqual(got, tt.sizes) {
+			t.Errorf("Sizes(%d, %d): got: %v; want: %v", tt.max, tt.n, got, tt.sizes)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import "time"
+
+// An Option configures a cache.
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (fn optionFunc) apply(o *options) { fn(o) }
+
+type options struct {
+	splitSize  bool
+	ttl        time.Duration
+	clock      Clock
+	weigher    any // Weigher[K, V]
+	evictFunc  any // EvictFunc[K, V]
+	ghostHooks any // GhostHooks[K]
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		clock: systemClock{},
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+// SplitSize returns an Option that divides the size given to NewSharded
+// evenly among the shards, rather than giving each shard the full size.
+// It has no effect on other caches.
+func SplitSize() Option {
+	return optionFunc(func(o *options) {
+		o.splitSize = true
+	})
+}
+
+// WithTTL returns an Option that sets the default time-to-live of items
+// written to the cache. By default, items do not expire.
+func WithTTL(ttl time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.ttl = ttl
+	})
+}
+
+// WithClock returns an Option that sets the clock used to tell time.
+// By default, the system clock is used.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(o *options) {
+		o.clock = clock
+	})
+}
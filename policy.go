@@ -0,0 +1,27 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+// A Policy is a cache with a replacement policy. Cache is the default,
+// and alternatives may be swapped in by changing only the constructor.
+// Package bursavich.dev/arc/policy provides LRU, 2Q, LIRS, and W-TinyLFU policies.
+type Policy[K comparable, V any] interface {
+	// Get reads the key's value from the cache.
+	Get(key K) (value V, found bool)
+	// Set writes the key's value to the cache.
+	Set(key K, value V)
+	// Delete deletes the key's value from the cache.
+	Delete(key K)
+	// Len returns the number of live items in the cache.
+	Len() int
+}
+
+var (
+	_ Policy[int, int] = (*Cache[int, int])(nil)
+	_ Policy[int, int] = (*SyncCache[int, int])(nil)
+	_ Policy[int, int] = (*ShardedCache[int, int])(nil)
+	_ Policy[int, int] = (*Classic[int, int])(nil)
+	_ Policy[int, int] = (*CAR[int, int])(nil)
+)
@@ -0,0 +1,219 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import "bursavich.dev/arc/internal/list"
+
+type lirsStatus uint8
+
+const (
+	lir         lirsStatus = iota // low inter-reference recency, resident
+	hirResident                   // high inter-reference recency, resident
+	hirGhost                      // high inter-reference recency, not resident
+)
+
+type lirsNode[K comparable, V any] struct {
+	key    K
+	val    V
+	status lirsStatus
+	s      *list.Element[*lirsNode[K, V]] // element in the stack, if any
+	q      *list.Element[*lirsNode[K, V]] // element in the queue or ghosts, if any
+}
+
+// LIRS is a Low Inter-reference Recency Set cache, as described by Jiang and Zhang.
+//
+// Items are ranked by the recency of their last two references rather than their
+// last one. Most of the cache holds the items with the lowest inter-reference recency,
+// the LIR items, and the remaining one percent holds the most recently added
+// HIR items. An HIR item that is referenced again before the oldest LIR item is promoted.
+// The number of non-resident HIR items tracked in the stack is bounded by the size of the cache.
+//
+// See:
+//
+//	https://dl.acm.org/doi/10.1145/511399.511340
+type LIRS[K comparable, V any] struct {
+	max    int
+	maxLIR int
+	numLIR int
+	tbl    map[K]*lirsNode[K, V]
+	stack  list.List[*lirsNode[K, V]] // the stack S; front is the top
+	queue  list.List[*lirsNode[K, V]] // resident HIR items; front is most recently added
+	ghosts list.List[*lirsNode[K, V]] // non-resident HIR items in S; front is most recently evicted
+}
+
+// NewLIRS creates a new LIRS cache that holds up to size items.
+func NewLIRS[K comparable, V any](size int) *LIRS[K, V] {
+	checkSize(size)
+	c := &LIRS[K, V]{
+		max:    size,
+		maxLIR: size - max(1, size/100),
+		tbl:    make(map[K]*lirsNode[K, V], size),
+	}
+	c.stack.Init()
+	c.queue.Init()
+	c.ghosts.Init()
+	return c
+}
+
+// Len returns the number of items in the cache.
+func (c *LIRS[K, V]) Len() int {
+	return c.numLIR + c.queue.Len()
+}
+
+// Get reads the key's value from the cache.
+func (c *LIRS[K, V]) Get(key K) (value V, found bool) {
+	n, ok := c.tbl[key]
+	if !ok || n.status == hirGhost {
+		return value, false
+	}
+	c.access(n)
+	return n.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *LIRS[K, V]) Set(key K, value V) {
+	n, ok := c.tbl[key]
+	if ok && n.status != hirGhost {
+		n.val = value
+		c.access(n)
+		return
+	}
+	if ok {
+		// Keep the ghost from being dropped while making room.
+		c.ghosts.Remove(n.q)
+		n.q = nil
+	}
+	if c.Len() >= c.max {
+		c.evict()
+	}
+	switch {
+	case c.numLIR < c.maxLIR:
+		if !ok {
+			n = &lirsNode[K, V]{key: key}
+			c.tbl[key] = n
+		}
+		n.val = value
+		n.status = lir
+		c.numLIR++
+		c.push(n)
+	case ok:
+		// A non-resident HIR item in the stack has a lower
+		// inter-reference recency than the oldest LIR item.
+		n.val = value
+		n.status = lir
+		c.numLIR++
+		c.push(n)
+		c.demote()
+	default:
+		n = &lirsNode[K, V]{key: key, val: value, status: hirResident}
+		c.tbl[key] = n
+		c.push(n)
+		n.q = c.queue.PushFront(n)
+	}
+}
+
+// Delete deletes the key's value from the cache.
+func (c *LIRS[K, V]) Delete(key K) {
+	n, ok := c.tbl[key]
+	if !ok {
+		return
+	}
+	c.drop(n)
+	c.prune()
+}
+
+// access records a reference to a resident item.
+func (c *LIRS[K, V]) access(n *lirsNode[K, V]) {
+	if n.status == lir {
+		c.push(n)
+		c.prune()
+		return
+	}
+	if n.s == nil {
+		// An HIR item not in the stack stays HIR.
+		c.push(n)
+		c.queue.MoveToFront(n.q)
+		return
+	}
+	c.queue.Remove(n.q)
+	n.q = nil
+	n.status = lir
+	c.numLIR++
+	c.push(n)
+	c.demote()
+}
+
+// push moves or inserts the item at the top of the stack.
+func (c *LIRS[K, V]) push(n *lirsNode[K, V]) {
+	if n.s != nil {
+		c.stack.MoveToFront(n.s)
+	} else {
+		n.s = c.stack.PushFront(n)
+	}
+}
+
+// demote moves LIR items from the bottom of the stack to the queue
+// until there are no more than the max, and then prunes the stack.
+func (c *LIRS[K, V]) demote() {
+	for c.numLIR > c.maxLIR {
+		c.prune()
+		n := c.stack.Remove(c.stack.Back())
+		n.s = nil
+		n.status = hirResident
+		n.q = c.queue.PushFront(n)
+		c.numLIR--
+	}
+	c.prune()
+}
+
+// prune removes HIR items from the bottom of the stack
+// so that the oldest item in the stack is an LIR item.
+func (c *LIRS[K, V]) prune() {
+	for e := c.stack.Back(); e != nil && e.Value.status != lir; e = c.stack.Back() {
+		n := c.stack.Remove(e)
+		n.s = nil
+		if n.status == hirGhost {
+			c.ghosts.Remove(n.q)
+			delete(c.tbl, n.key)
+		}
+	}
+}
+
+// evict removes the oldest resident HIR item. It remains in the stack,
+// if present, as a non-resident HIR item. There is always a resident
+// HIR item when the cache is full, because LIR items never fill it.
+func (c *LIRS[K, V]) evict() {
+	n := c.queue.Remove(c.queue.Back())
+	n.q = nil
+	var zero V
+	n.val = zero
+	if n.s == nil {
+		delete(c.tbl, n.key)
+		return
+	}
+	n.status = hirGhost
+	n.q = c.ghosts.PushFront(n)
+	if c.ghosts.Len() > c.max {
+		c.drop(c.ghosts.Back().Value)
+	}
+}
+
+// drop removes all record of the item.
+func (c *LIRS[K, V]) drop(n *lirsNode[K, V]) {
+	if n.s != nil {
+		c.stack.Remove(n.s)
+		n.s = nil
+	}
+	switch n.status {
+	case lir:
+		c.numLIR--
+	case hirResident:
+		c.queue.Remove(n.q)
+	case hirGhost:
+		c.ghosts.Remove(n.q)
+	}
+	n.q = nil
+	delete(c.tbl, n.key)
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+func lirsKeys[K comparable, V any](l *list.List[*lirsNode[K, V]]) []K {
+	a := []K{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		a = append(a, e.Value.key)
+	}
+	return a
+}
+
+func TestLIRS(t *testing.T) {
+	tests := []struct {
+		cmd    string
+		key    int
+		found  bool
+		stack  []int // top first
+		queue  []int // newest first
+		ghosts []int // newest first
+	}{
+		{cmd: "set", key: 0, stack: []int{0}, queue: []int{}, ghosts: []int{}},
+		{cmd: "set", key: 1, stack: []int{1, 0}, queue: []int{}, ghosts: []int{}},
+		{cmd: "set", key: 2, stack: []int{2, 1, 0}, queue: []int{2}, ghosts: []int{}},
+		{cmd: "set", key: 3, stack: []int{3, 2, 1, 0}, queue: []int{3}, ghosts: []int{2}},
+		{cmd: "get", key: 0, found: true, stack: []int{0, 3, 2, 1}, queue: []int{3}, ghosts: []int{2}},
+		{cmd: "get", key: 2, stack: []int{0, 3, 2, 1}, queue: []int{3}, ghosts: []int{2}},
+		{cmd: "set", key: 2, stack: []int{2, 0}, queue: []int{1}, ghosts: []int{}},
+		{cmd: "get", key: 1, found: true, stack: []int{1, 2, 0}, queue: []int{1}, ghosts: []int{}},
+		{cmd: "get", key: 1, found: true, stack: []int{1, 2}, queue: []int{0}, ghosts: []int{}},
+		{cmd: "get", key: 3},
+		{cmd: "set", key: 4, stack: []int{4, 1, 2}, queue: []int{4}, ghosts: []int{}},
+		{cmd: "set", key: 5, stack: []int{5, 4, 1, 2}, queue: []int{5}, ghosts: []int{4}},
+		{cmd: "del", key: 2, stack: []int{5, 4, 1}, queue: []int{5}, ghosts: []int{4}},
+		{cmd: "del", key: 1, stack: []int{}, queue: []int{5}, ghosts: []int{}},
+		{cmd: "set", key: 6, stack: []int{6}, queue: []int{5}, ghosts: []int{}},
+	}
+	c := NewLIRS[int, int](3)
+	for i, tt := range tests {
+		switch tt.cmd {
+		case "get":
+			if _, found := c.Get(tt.key); found != tt.found {
+				t.Fatalf("step %d: %s %d: got: %v; want: %v", i, tt.cmd, tt.key, found, tt.found)
+			}
+			if tt.stack == nil {
+				continue
+			}
+		case "set":
+			c.Set(tt.key, tt.key)
+		case "del":
+			c.Delete(tt.key)
+		}
+		if got := lirsKeys(&c.stack); !reflect.DeepEqual(got, tt.stack) {
+			t.Fatalf("step %d: %s %d: unexpected stack: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.stack)
+		}
+		if got := lirsKeys(&c.queue); !reflect.DeepEqual(got, tt.queue) {
+			t.Fatalf("step %d: %s %d: unexpected queue: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.queue)
+		}
+		if got := lirsKeys(&c.ghosts); !reflect.DeepEqual(got, tt.ghosts) {
+			t.Fatalf("step %d: %s %d: unexpected ghosts: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.ghosts)
+		}
+	}
+}
+
+// TestLIRSInvariants checks the structure of the stack and queue
+// against a long pseudorandom sequence of operations.
+func TestLIRSInvariants(t *testing.T) {
+	const size = 20
+	c := NewLIRS[int, int](size)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		key := int(rng.ExpFloat64() * size)
+		switch rng.Int31n(10) {
+		case 0:
+			c.Delete(key)
+		default:
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, key)
+			}
+		}
+		numLIR := 0
+		for e := c.stack.Front(); e != nil; e = e.Next() {
+			if n := e.Value; n.s != e {
+				t.Fatalf("step %d: node %d has stale stack element", i, n.key)
+			} else if n.status == lir {
+				numLIR++
+			}
+		}
+		if numLIR != c.numLIR || numLIR > c.maxLIR {
+			t.Fatalf("step %d: LIR items in stack: %d; counted: %d; max: %d", i, numLIR, c.numLIR, c.maxLIR)
+		}
+		if e := c.stack.Back(); e != nil && e.Value.status != lir {
+			t.Fatalf("step %d: bottom of stack is not an LIR item", i)
+		}
+		if n := c.Len(); n > size {
+			t.Fatalf("step %d: unexpected length: got: %d; want <= %d", i, n, size)
+		}
+		if n := len(c.tbl); n != c.numLIR+c.queue.Len()+c.ghosts.Len() {
+			t.Fatalf("step %d: table has %d items; want %d", i, n, c.numLIR+c.queue.Len()+c.ghosts.Len())
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import "bursavich.dev/arc/internal/list"
+
+// LRU is a least-recently-used cache.
+type LRU[K comparable, V any] struct {
+	max int
+	tbl map[K]*list.Element[entry[K, V]]
+	lru list.List[entry[K, V]] // front is most recently used
+}
+
+// NewLRU creates a new LRU cache that holds up to size items.
+func NewLRU[K comparable, V any](size int) *LRU[K, V] {
+	checkSize(size)
+	c := &LRU[K, V]{
+		max: size,
+		tbl: make(map[K]*list.Element[entry[K, V]], size),
+	}
+	c.lru.Init()
+	return c
+}
+
+// Len returns the number of items in the cache.
+func (c *LRU[K, V]) Len() int {
+	return len(c.tbl)
+}
+
+// Get reads the key's value from the cache.
+func (c *LRU[K, V]) Get(key K) (value V, found bool) {
+	e, ok := c.tbl[key]
+	if !ok {
+		return value, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *LRU[K, V]) Set(key K, value V) {
+	if e, ok := c.tbl[key]; ok {
+		e.Value.val = value
+		c.lru.MoveToFront(e)
+		return
+	}
+	if len(c.tbl) >= c.max {
+		delete(c.tbl, c.lru.Remove(c.lru.Back()).key)
+	}
+	c.tbl[key] = c.lru.PushFront(entry[K, V]{key: key, val: value})
+}
+
+// Delete deletes the key's value from the cache.
+func (c *LRU[K, V]) Delete(key K) {
+	if e, ok := c.tbl[key]; ok {
+		c.lru.Remove(e)
+		delete(c.tbl, key)
+	}
+}
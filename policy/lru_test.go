@@ -0,0 +1,37 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+func keys[K comparable, V any](l *list.List[entry[K, V]]) []K {
+	a := []K{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		a = append(a, e.Value.key)
+	}
+	return a
+}
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[int, int](3)
+	c.Set(0, 0)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(0)
+	c.Set(3, 3)
+	if got, want := keys(&c.lru), []int{3, 0, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", got, want)
+	}
+	c.Set(2, 2)
+	c.Set(4, 4)
+	if got, want := keys(&c.lru), []int{4, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected keys: got: %v; want: %v", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Package policy implements alternative cache replacement policies
+// for comparison with the adaptive replacement cache.
+//
+// Each policy satisfies arc.Policy, so it may be swapped in for an arc.Cache
+// by changing only the constructor. Like arc.Cache, they are not safe
+// for concurrent access.
+package policy
+
+import "bursavich.dev/arc"
+
+var (
+	_ arc.Policy[int, int] = (*LRU[int, int])(nil)
+	_ arc.Policy[int, int] = (*TwoQ[int, int])(nil)
+	_ arc.Policy[int, int] = (*LIRS[int, int])(nil)
+	_ arc.Policy[int, int] = (*TinyLFU[int, int])(nil)
+)
+
+type entry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+func checkSize(size int) {
+	if size <= 0 {
+		panic("policy: size must be greater than 0")
+	}
+}
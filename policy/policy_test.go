@@ -0,0 +1,83 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"bursavich.dev/arc"
+)
+
+var policies = []struct {
+	name string
+	new  func(size int) arc.Policy[int, string]
+}{
+	{"ARC", func(size int) arc.Policy[int, string] { return arc.New[int, string](size) }},
+	{"LRU", func(size int) arc.Policy[int, string] { return NewLRU[int, string](size) }},
+	{"2Q", func(size int) arc.Policy[int, string] { return New2Q[int, string](size) }},
+	{"LIRS", func(size int) arc.Policy[int, string] { return NewLIRS[int, string](size) }},
+	{"TinyLFU", func(size int) arc.Policy[int, string] { return NewTinyLFU[int, string](size) }},
+}
+
+func TestPolicy(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			for _, size := range []int{1, 2, 3, 10, 100} {
+				c := p.new(size)
+				c.Set(-1, "x")
+				if val, ok := c.Get(-1); !ok || val != "x" {
+					t.Fatalf("size %d: Get(-1): got: (%q, %v); want: (%q, true)", size, val, ok, "x")
+				}
+				c.Set(-1, "y")
+				if val, ok := c.Get(-1); !ok || val != "y" {
+					t.Fatalf("size %d: Get(-1): got: (%q, %v); want: (%q, true)", size, val, ok, "y")
+				}
+				c.Delete(-1)
+				if val, ok := c.Get(-1); ok {
+					t.Fatalf("size %d: Get(-1): got: (%q, %v); want: (%q, false)", size, val, ok, "")
+				}
+				if n := c.Len(); n != 0 {
+					t.Fatalf("size %d: unexpected length: got: %d; want: 0", size, n)
+				}
+
+				rng := rand.New(rand.NewSource(int64(size)))
+				for i := 0; i < 100*size; i++ {
+					key := int(rng.Int31n(int32(4 * size)))
+					switch rng.Int31n(10) {
+					case 0:
+						c.Delete(key)
+						if _, ok := c.Get(key); ok {
+							t.Fatalf("size %d: Get(%d): found after Delete", size, key)
+						}
+					case 1, 2, 3:
+						c.Set(key, strconv.Itoa(key))
+					default:
+						if val, ok := c.Get(key); ok && val != strconv.Itoa(key) {
+							t.Fatalf("size %d: Get(%d): got: %q; want: %q", size, key, val, strconv.Itoa(key))
+						}
+					}
+					if n := c.Len(); n > size {
+						t.Fatalf("size %d: unexpected length: got: %d; want <= %d", size, n, size)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPolicySize(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic for size 0")
+				}
+			}()
+			p.new(0)
+		})
+	}
+}
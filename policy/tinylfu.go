@@ -0,0 +1,181 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"hash/maphash"
+	"math/bits"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+// TinyLFU is a W-TinyLFU cache, as described by Einziger, Friedman, and Manes.
+//
+// New items enter a small LRU window that holds one percent of the cache.
+// Items that fall out of the window compete for admission to the main cache,
+// a segmented LRU, against its next victim: the candidate is admitted only if
+// its estimated frequency is greater. Frequencies are estimated from Gets by
+// a count-min sketch of 4-bit counters that are periodically halved, so that
+// the estimates favor recent history.
+//
+// See:
+//
+//	https://arxiv.org/abs/1512.00727
+type TinyLFU[K comparable, V any] struct {
+	maxWindow    int
+	maxMain      int
+	maxProtected int
+	tbl          map[K]*list.Element[entry[K, V]]
+	window       list.List[entry[K, V]] // front is most recently used
+	probation    list.List[entry[K, V]] // front is most recently used
+	protected    list.List[entry[K, V]] // front is most recently used
+	sketch       sketch[K]
+}
+
+// NewTinyLFU creates a new W-TinyLFU cache that holds up to size items.
+func NewTinyLFU[K comparable, V any](size int) *TinyLFU[K, V] {
+	checkSize(size)
+	window := max(1, size/100)
+	c := &TinyLFU[K, V]{
+		maxWindow:    window,
+		maxMain:      size - window,
+		maxProtected: (size - window) * 4 / 5,
+		tbl:          make(map[K]*list.Element[entry[K, V]], size),
+	}
+	c.window.Init()
+	c.probation.Init()
+	c.protected.Init()
+	c.sketch.init(size)
+	return c
+}
+
+// Len returns the number of items in the cache.
+func (c *TinyLFU[K, V]) Len() int {
+	return len(c.tbl)
+}
+
+// Get reads the key's value from the cache.
+func (c *TinyLFU[K, V]) Get(key K) (value V, found bool) {
+	c.sketch.add(key)
+	e, ok := c.tbl[key]
+	if !ok {
+		return value, false
+	}
+	return c.access(e).Value.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *TinyLFU[K, V]) Set(key K, value V) {
+	if e, ok := c.tbl[key]; ok {
+		c.access(e).Value.val = value
+		return
+	}
+	c.tbl[key] = c.window.PushFront(entry[K, V]{key: key, val: value})
+	if c.window.Len() > c.maxWindow {
+		c.admit(c.window.Remove(c.window.Back()))
+	}
+}
+
+// Delete deletes the key's value from the cache.
+func (c *TinyLFU[K, V]) Delete(key K) {
+	if e, ok := c.tbl[key]; ok {
+		e.List().Remove(e)
+		delete(c.tbl, key)
+	}
+}
+
+// access records a reference to the item, promoting it from probation
+// to the protected segment, and returns its possibly new element.
+func (c *TinyLFU[K, V]) access(e *list.Element[entry[K, V]]) *list.Element[entry[K, V]] {
+	switch e.List() {
+	case &c.window:
+		c.window.MoveToFront(e)
+	case &c.protected:
+		c.protected.MoveToFront(e)
+	default:
+		it := c.probation.Remove(e)
+		e = c.protected.PushFront(it)
+		c.tbl[it.key] = e
+		if c.protected.Len() > c.maxProtected {
+			it := c.protected.Remove(c.protected.Back())
+			c.tbl[it.key] = c.probation.PushFront(it)
+		}
+	}
+	return e
+}
+
+// admit adds the candidate evicted from the window to the main cache
+// if there's room or if it's estimated to be more frequent than the main
+// cache's victim, which is evicted in its place.
+func (c *TinyLFU[K, V]) admit(it entry[K, V]) {
+	if c.probation.Len()+c.protected.Len() < c.maxMain {
+		c.tbl[it.key] = c.probation.PushFront(it)
+		return
+	}
+	victims := &c.probation
+	if victims.Len() == 0 {
+		victims = &c.protected
+	}
+	if victims.Len() == 0 || c.sketch.estimate(it.key) <= c.sketch.estimate(victims.Back().Value.key) {
+		delete(c.tbl, it.key)
+		return
+	}
+	delete(c.tbl, victims.Remove(victims.Back()).key)
+	c.tbl[it.key] = c.probation.PushFront(it)
+}
+
+const sketchDepth = 4
+
+// sketch is a count-min sketch of 4-bit counters.
+type sketch[K comparable] struct {
+	seed  maphash.Seed
+	mask  uint64
+	rows  [sketchDepth][]uint8
+	adds  int
+	reset int // halve counters after this many additions
+}
+
+func (s *sketch[K]) init(size int) {
+	width := 1 << bits.Len(uint(max(16, size)-1))
+	s.seed = maphash.MakeSeed()
+	s.mask = uint64(width - 1)
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	s.reset = 10 * size
+}
+
+// add increments the key's estimated frequency.
+func (s *sketch[K]) add(key K) {
+	h := maphash.Comparable(s.seed, key)
+	for i := range s.rows {
+		if p := &s.rows[i][s.index(h, i)]; *p < 15 {
+			*p++
+		}
+	}
+	if s.adds++; s.adds >= s.reset {
+		for _, row := range s.rows {
+			for j := range row {
+				row[j] >>= 1
+			}
+		}
+		s.adds /= 2
+	}
+}
+
+// estimate returns the key's estimated frequency.
+func (s *sketch[K]) estimate(key K) uint8 {
+	h := maphash.Comparable(s.seed, key)
+	n := uint8(15)
+	for i := range s.rows {
+		n = min(n, s.rows[i][s.index(h, i)])
+	}
+	return n
+}
+
+func (s *sketch[K]) index(h uint64, i int) uint64 {
+	h1, h2 := h&0xffffffff, h>>32|1
+	return (h1 + uint64(i)*h2) & s.mask
+}
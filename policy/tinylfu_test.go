@@ -0,0 +1,64 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTinyLFU(t *testing.T) {
+	c := NewTinyLFU[int, int](100)
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+	if got, want := keys(&c.window), []int{99}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected window: got: %v; want: %v", got, want)
+	}
+	if n := c.probation.Len(); n != 99 {
+		t.Fatalf("unexpected probation length: got: %d; want: 99", n)
+	}
+
+	// A frequently requested key is admitted in place of the main cache's victim.
+	for i := 0; i < 5; i++ {
+		c.Get(1000)
+	}
+	c.Set(1000, 1000) // 99 is rejected
+	c.Set(2000, 2000) // 1000 is admitted and 0 is evicted
+	for key, want := range map[int]bool{0: false, 99: false, 1000: true, 2000: true} {
+		if _, got := c.tbl[key]; got != want {
+			t.Errorf("key %d: got: %v; want: %v", key, got, want)
+		}
+	}
+	if got, want := c.probation.Front().Value.key, 1000; got != want {
+		t.Errorf("unexpected front of probation: got: %d; want: %d", got, want)
+	}
+
+	// A hit in probation promotes the item to the protected segment.
+	c.Get(1)
+	if got, want := keys(&c.protected), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected protected: got: %v; want: %v", got, want)
+	}
+	if n := c.Len(); n != 100 {
+		t.Fatalf("unexpected length: got: %d; want: 100", n)
+	}
+}
+
+func TestSketch(t *testing.T) {
+	var s sketch[int]
+	s.init(4) // counters are halved every 40 additions
+	for i := 0; i < 20; i++ {
+		s.add(1)
+	}
+	if got, want := s.estimate(1), uint8(15); got != want {
+		t.Fatalf("unexpected saturated estimate: got: %d; want: %d", got, want)
+	}
+	for i := 0; i < 20; i++ {
+		s.add(1)
+	}
+	if got, want := s.estimate(1), uint8(7); got != want {
+		t.Fatalf("unexpected halved estimate: got: %d; want: %d", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import "bursavich.dev/arc/internal/list"
+
+// TwoQ is a 2Q cache, as described by Johnson and Shasha.
+//
+// New items enter a FIFO queue, A1in, that holds a quarter of the cache.
+// Items that fall out of A1in are remembered in a ghost queue, A1out,
+// of half the cache's size. Only items that are set again while in A1out
+// are admitted to the main LRU list, Am, so a scan can't flush it.
+//
+// See:
+//
+//	https://www.vldb.org/conf/1994/P439.PDF
+type TwoQ[K comparable, V any] struct {
+	max  int
+	kin  int // max length of A1in
+	kout int // max length of A1out
+	tbl  map[K]*list.Element[entry[K, V]]
+	in   list.List[entry[K, V]] // A1in; front is most recently added
+	out  list.List[entry[K, V]] // A1out; front is most recently added
+	am   list.List[entry[K, V]] // Am; front is most recently used
+}
+
+// New2Q creates a new 2Q cache that holds up to size items.
+func New2Q[K comparable, V any](size int) *TwoQ[K, V] {
+	checkSize(size)
+	c := &TwoQ[K, V]{
+		max:  size,
+		kin:  max(1, size/4),
+		kout: max(1, size/2),
+		tbl:  make(map[K]*list.Element[entry[K, V]], size),
+	}
+	c.in.Init()
+	c.out.Init()
+	c.am.Init()
+	return c
+}
+
+// Len returns the number of items in the cache.
+func (c *TwoQ[K, V]) Len() int {
+	return c.in.Len() + c.am.Len()
+}
+
+// Get reads the key's value from the cache.
+func (c *TwoQ[K, V]) Get(key K) (value V, found bool) {
+	e, ok := c.tbl[key]
+	switch {
+	case !ok || e.List() == &c.out:
+		return value, false
+	case e.List() == &c.am:
+		c.am.MoveToFront(e)
+	}
+	return e.Value.val, true
+}
+
+// Set writes the key's value to the cache.
+func (c *TwoQ[K, V]) Set(key K, value V) {
+	e, ok := c.tbl[key]
+	switch {
+	case !ok:
+		c.reclaim()
+		c.tbl[key] = c.in.PushFront(entry[K, V]{key: key, val: value})
+	case e.List() == &c.out:
+		c.out.Remove(e)
+		c.reclaim()
+		c.tbl[key] = c.am.PushFront(entry[K, V]{key: key, val: value})
+	case e.List() == &c.am:
+		e.Value.val = value
+		c.am.MoveToFront(e)
+	default:
+		e.Value.val = value
+	}
+}
+
+// Delete deletes the key's value from the cache.
+func (c *TwoQ[K, V]) Delete(key K) {
+	if e, ok := c.tbl[key]; ok {
+		e.List().Remove(e)
+		delete(c.tbl, key)
+	}
+}
+
+// reclaim frees a slot for a new item if the cache is full.
+func (c *TwoQ[K, V]) reclaim() {
+	if c.Len() < c.max {
+		return
+	}
+	if c.in.Len() > c.kin || c.am.Len() == 0 {
+		it := c.in.Remove(c.in.Back())
+		c.tbl[it.key] = c.out.PushFront(entry[K, V]{key: it.key})
+		if c.out.Len() > c.kout {
+			delete(c.tbl, c.out.Remove(c.out.Back()).key)
+		}
+		return
+	}
+	delete(c.tbl, c.am.Remove(c.am.Back()).key)
+}
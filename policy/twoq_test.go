@@ -0,0 +1,57 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTwoQ(t *testing.T) {
+	tests := []struct {
+		cmd string
+		key int
+		in  []int // A1in, newest first
+		out []int // A1out, newest first
+		am  []int // Am, most recently used first
+	}{
+		{cmd: "set", key: 0, in: []int{0}, out: []int{}, am: []int{}},
+		{cmd: "set", key: 1, in: []int{1, 0}, out: []int{}, am: []int{}},
+		{cmd: "set", key: 2, in: []int{2, 1, 0}, out: []int{}, am: []int{}},
+		{cmd: "set", key: 3, in: []int{3, 2, 1, 0}, out: []int{}, am: []int{}},
+		{cmd: "get", key: 0, in: []int{3, 2, 1, 0}, out: []int{}, am: []int{}},
+		{cmd: "set", key: 4, in: []int{4, 3, 2, 1}, out: []int{0}, am: []int{}},
+		{cmd: "set", key: 0, in: []int{4, 3, 2}, out: []int{1}, am: []int{0}},
+		{cmd: "set", key: 5, in: []int{5, 4, 3}, out: []int{2, 1}, am: []int{0}},
+		{cmd: "set", key: 6, in: []int{6, 5, 4}, out: []int{3, 2}, am: []int{0}},
+		{cmd: "set", key: 3, in: []int{6, 5}, out: []int{4, 2}, am: []int{3, 0}},
+		{cmd: "get", key: 0, in: []int{6, 5}, out: []int{4, 2}, am: []int{0, 3}},
+		{cmd: "set", key: 7, in: []int{7, 6}, out: []int{5, 4}, am: []int{0, 3}},
+		{cmd: "set", key: 8, in: []int{8, 7}, out: []int{6, 5}, am: []int{0, 3}},
+		{cmd: "del", key: 5, in: []int{8, 7}, out: []int{6}, am: []int{0, 3}},
+		{cmd: "del", key: 3, in: []int{8, 7}, out: []int{6}, am: []int{0}},
+		{cmd: "set", key: 6, in: []int{8, 7}, out: []int{}, am: []int{6, 0}},
+	}
+	c := New2Q[int, int](4)
+	for i, tt := range tests {
+		switch tt.cmd {
+		case "get":
+			c.Get(tt.key)
+		case "set":
+			c.Set(tt.key, tt.key)
+		case "del":
+			c.Delete(tt.key)
+		}
+		if got := keys(&c.in); !reflect.DeepEqual(got, tt.in) {
+			t.Fatalf("step %d: %s %d: unexpected A1in: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.in)
+		}
+		if got := keys(&c.out); !reflect.DeepEqual(got, tt.out) {
+			t.Fatalf("step %d: %s %d: unexpected A1out: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.out)
+		}
+		if got := keys(&c.am); !reflect.DeepEqual(got, tt.am) {
+			t.Fatalf("step %d: %s %d: unexpected Am: got: %v; want: %v", i, tt.cmd, tt.key, got, tt.am)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// ShardedCache is an adaptive replacement cache partitioned into shards.
+// It is safe for concurrent access.
+//
+// Keys are hashed to one of several independent caches, each with its own lock,
+// live and dead lists, and pivot. Operations on keys in different shards do not
+// contend with each other, but each shard adapts only to its own share of the workload.
+type ShardedCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	split  bool
+	shards []*SyncCache[K, V]
+}
+
+// NewSharded creates a new ShardedCache with the given number of shards.
+// By default each shard holds size items. With the SplitSize option,
+// size is divided evenly among the shards.
+func NewSharded[K comparable, V any](shards, size int, opts ...Option) *ShardedCache[K, V] {
+	if shards <= 0 {
+		panic("arc: shards must be greater than 0")
+	}
+	if size <= 0 {
+		panic("arc: size must be greater than 0")
+	}
+	o := newOptions(opts)
+	if o.splitSize && size < shards {
+		panic("arc: size must not be less than shards")
+	}
+	c := &ShardedCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		split:  o.splitSize,
+		shards: make([]*SyncCache[K, V], shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = NewSync[K, V](c.shardSize(i, size), opts...)
+	}
+	return c
+}
+
+// shardSize returns the size of the i-th shard of a cache with the given size.
+func (c *ShardedCache[K, V]) shardSize(i, size int) int {
+	if !c.split {
+		return size
+	}
+	n := size / len(c.shards)
+	if i < size%len(c.shards) {
+		n++
+	}
+	return n
+}
+
+// Len returns the number of live items in the cache.
+// It includes expired items that have not yet been removed.
+func (c *ShardedCache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Get reads the key's value from the cache.
+func (c *ShardedCache[K, V]) Get(key K) (value V, found bool) {
+	return c.shard(key).Get(key)
+}
+
+// Clear removes all live items from the cache.
+// See Cache.Clear for details.
+func (c *ShardedCache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+// Purge removes all live and dead items from the cache and resets the pivots.
+// See Cache.Purge for details.
+func (c *ShardedCache[K, V]) Purge() {
+	for _, s := range c.shards {
+		s.Purge()
+	}
+}
+
+// Resize changes the max number of live items in each shard, or in the cache
+// as a whole if it was created with SplitSize. See Cache.Resize for details.
+func (c *ShardedCache[K, V]) Resize(size int) {
+	if size <= 0 {
+		panic("arc: size must be greater than 0")
+	}
+	if c.split && size < len(c.shards) {
+		panic("arc: size must not be less than shards")
+	}
+	for i, s := range c.shards {
+		s.Resize(c.shardSize(i, size))
+	}
+}
+
+// Peek reads the key's value from the cache without updating its recency or frequency.
+func (c *ShardedCache[K, V]) Peek(key K) (value V, found bool) {
+	return c.shard(key).Peek(key)
+}
+
+// Contains reports whether the key's value is in the cache
+// without updating its recency or frequency.
+func (c *ShardedCache[K, V]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// WasRecentlyEvicted reports whether the key's value was recently evicted
+// and the key remains in the dead cache, where setting it again would adapt the pivot.
+func (c *ShardedCache[K, V]) WasRecentlyEvicted(key K) bool {
+	return c.shard(key).WasRecentlyEvicted(key)
+}
+
+// Set writes the key's value to the cache.
+// It expires after the cache's default time-to-live, if any.
+func (c *ShardedCache[K, V]) Set(key K, value V) {
+	c.shard(key).Set(key, value)
+}
+
+// SetWithTTL writes the key's value to the cache.
+// It expires after the given time-to-live, or never if ttl is not positive.
+func (c *ShardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.shard(key).SetWithTTL(key, value, ttl)
+}
+
+// Delete deletes the key's value from the cache.
+func (c *ShardedCache[K, V]) Delete(key K) {
+	c.shard(key).Delete(key)
+}
+
+func (c *ShardedCache[K, V]) shard(key K) *SyncCache[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestShardedSize(t *testing.T) {
+	tests := []struct {
+		shards int
+		size   int
+		opts   []Option
+		want   []int
+	}{
+		{shards: 1, size: 10, want: []int{10}},
+		{shards: 3, size: 10, want: []int{10, 10, 10}},
+		{shards: 3, size: 10, opts: []Option{SplitSize()}, want: []int{4, 3, 3}},
+		{shards: 4, size: 8, opts: []Option{SplitSize()}, want: []int{2, 2, 2, 2}},
+	}
+	for _, tt := range tests {
+		c := NewSharded[int, int](tt.shards, tt.size, tt.opts...)
+		var got []int
+		for _, s := range c.shards {
+			got = append(got, int(s.c.max))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("NewSharded(%d, %d): unexpected shard sizes; got: %v; want: %v", tt.shards, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	const (
+		shards     = 8
+		size       = 64
+		keys       = size * 4
+		goroutines = 16
+		ops        = 10000
+	)
+	c := NewSharded[string, int](shards, size, SplitSize())
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				key := rng.Intn(keys)
+				switch n := rng.Intn(20); {
+				case n < 10:
+					if val, ok := c.Get(strconv.Itoa(key)); ok && val != key {
+						t.Errorf("Get(%d): unexpected value: %d", key, val)
+						return
+					}
+				case n < 18:
+					c.Set(strconv.Itoa(key), key)
+				default:
+					c.Delete(strconv.Itoa(key))
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > size {
+		t.Fatalf("Len(): got: %d; want <= %d", n, size)
+	}
+	for i, s := range c.shards {
+		if n := s.Len(); int64(n) > s.c.max {
+			t.Fatalf("shard %d: Len(): got: %d; want <= %d", i, n, s.c.max)
+		}
+	}
+}
+
+// Run with -cpu=1,2,4,8,... to see how throughput scales with GOMAXPROCS.
+func BenchmarkConcurrent(b *testing.B) {
+	const (
+		size = 1 << 16
+		keys = size * 2
+	)
+	run := func(b *testing.B, c interface {
+		Get(int) (int, bool)
+		Set(int, int)
+	}) {
+		b.RunParallel(func(pb *testing.PB) {
+			rng := rand.New(rand.NewSource(rand.Int63()))
+			for pb.Next() {
+				key := rng.Intn(keys)
+				if _, ok := c.Get(key); !ok {
+					c.Set(key, key)
+				}
+			}
+		})
+	}
+	b.Run("Sync", func(b *testing.B) {
+		run(b, NewSync[int, int](size))
+	})
+	for _, shards := range []int{4, 16, 64} {
+		b.Run(fmt.Sprintf("Sharded/shards=%d", shards), func(b *testing.B) {
+			run(b, NewSharded[int, int](shards, size, SplitSize()))
+		})
+	}
+}
+
+func TestShardedResize(t *testing.T) {
+	c := NewSharded[int, int](3, 10, SplitSize())
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+	c.Resize(5)
+	var got []int
+	for _, s := range c.shards {
+		got = append(got, int(s.c.max))
+		if n := s.Len(); int64(n) > s.c.max {
+			t.Errorf("unexpected shard len: %d; max: %d", n, s.c.max)
+		}
+	}
+	if want := []int{2, 2, 1}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("unexpected shard sizes; got: %v; want: %v", got, want)
+	}
+}
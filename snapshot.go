@@ -0,0 +1,267 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A Codec marshals and unmarshals values of type T.
+type Codec[T any] interface {
+	Marshal(v T) ([]byte, error)
+	Unmarshal(b []byte) (T, error)
+}
+
+// JSONCodec is a Codec that uses package encoding/json.
+type JSONCodec[T any] struct{}
+
+// Marshal returns the JSON encoding of v.
+func (JSONCodec[T]) Marshal(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal parses the JSON encoded value in b.
+func (JSONCodec[T]) Unmarshal(b []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+const (
+	snapshotMagic  = "arc\x01"
+	snapshotMaxLen = 1 << 30 // max length of a marshaled key or value
+
+	// snapshotMaxHint is the max number of items for which to preallocate room,
+	// so a corrupt size can't make Restore allocate a lot of memory up front.
+	snapshotMaxHint = 1 << 16
+)
+
+var errSnapshot = errors.New("arc: invalid snapshot")
+
+// Snapshot writes the full state of the cache to w: its size, its pivot,
+// and the keys of its live and dead lists, from least to most recently used,
+// along with the values, expirations, and weights of its live items.
+// Keys and values are marshaled with the given codecs.
+func (c *Cache[K, V]) Snapshot(w io.Writer, keys Codec[K], vals Codec[V]) error {
+	sw := snapshotWriter{w: bufio.NewWriter(w)}
+	sw.string(snapshotMagic)
+	sw.uvarint(uint64(c.max))
+	sw.uvarint(uint64(c.pivot))
+	s := &c.items
+	for _, l := range []int32{liveMRU, liveMFU} {
+		sw.uvarint(uint64(s.lens[l]))
+		for i := s.back(l); i != l; i = s.slots[i].prev {
+			sw.marshal(keys.Marshal(s.slots[i].key))
+			sw.marshal(vals.Marshal(s.slots[i].val))
+			sw.varint(s.slots[i].exp)
+			sw.uvarint(uint64(s.slots[i].wt))
+		}
+	}
+	for _, l := range []int32{deadMRU, deadMFU} {
+		sw.uvarint(uint64(s.lens[l]))
+		for i := s.back(l); i != l; i = s.slots[i].prev {
+			sw.marshal(keys.Marshal(s.slots[i].key))
+			sw.uvarint(uint64(s.slots[i].wt))
+		}
+	}
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.w.Flush()
+}
+
+// Restore reads a cache from a snapshot written by Snapshot. Keys and values are
+// unmarshaled with the given codecs. The cache is configured with the given options,
+// but its size is that of the snapshot.
+//
+// Live items are weighed by the restored cache, as if by Set, rather than taking
+// their weights from the snapshot, so the options needn't match those of the cache
+// that wrote it. Dead items have no values to weigh, so they keep their weights from
+// the snapshot if the cache has a Weigher and weigh 1 otherwise. It's an error if the
+// live or dead items weigh more than the size.
+func Restore[K comparable, V any](r io.Reader, keys Codec[K], vals Codec[V], opts ...Option) (*Cache[K, V], error) {
+	sr := snapshotReader{r: bufio.NewReader(r)}
+	if magic := sr.bytes(uint64(len(snapshotMagic))); sr.err == nil && string(magic) != snapshotMagic {
+		return nil, fmt.Errorf("%w: bad header", errSnapshot)
+	}
+	size := sr.uvarint()
+	pivot := sr.uvarint()
+	if sr.err != nil {
+		return nil, sr.err
+	}
+	if size == 0 || size > math.MaxInt || pivot > size {
+		return nil, fmt.Errorf("%w: bad size or pivot", errSnapshot)
+	}
+	c := newCache[K, V](int(size), int(min(int64(size), snapshotMaxHint)), opts)
+	c.pivot = int64(pivot)
+	for _, l := range []int32{liveMRU, liveMFU} {
+		for n := sr.uvarint(); n > 0 && sr.err == nil; n-- {
+			key := unmarshal(&sr, keys)
+			val := unmarshal(&sr, vals)
+			exp := sr.varint()
+			wt := int64(sr.uvarint())
+			if sr.err == nil && restorable(&sr, c, key, wt) {
+				c.items.push(l, key, val, exp, c.weigh(key, val))
+			}
+		}
+	}
+	for _, l := range []int32{deadMRU, deadMFU} {
+		for n := sr.uvarint(); n > 0 && sr.err == nil; n-- {
+			var zero V
+			key := unmarshal(&sr, keys)
+			wt := int64(sr.uvarint())
+			if sr.err == nil && restorable(&sr, c, key, wt) {
+				if c.weigher == nil {
+					wt = 1
+				}
+				c.items.push(l, key, zero, 0, wt)
+			}
+		}
+	}
+	if sr.err != nil {
+		return nil, sr.err
+	}
+	if c.items.liveWeight() > c.max || c.items.deadWeight() > c.max {
+		return nil, fmt.Errorf("%w: too large", errSnapshot)
+	}
+	c.gauge()
+	return c, nil
+}
+
+// Snapshot writes the full state of the cache to w.
+// See Cache.Snapshot for details.
+func (c *SyncCache[K, V]) Snapshot(w io.Writer, keys Codec[K], vals Codec[V]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Snapshot(w, keys, vals)
+}
+
+// RestoreSync reads a cache from a snapshot written by Snapshot.
+// See Restore for details.
+func RestoreSync[K comparable, V any](r io.Reader, keys Codec[K], vals Codec[V], opts ...Option) (*SyncCache[K, V], error) {
+	c, err := Restore(r, keys, vals, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncCache[K, V]{c: c}, nil
+}
+
+type snapshotWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (w *snapshotWriter) string(s string) {
+	if w.err == nil {
+		_, w.err = w.w.WriteString(s)
+	}
+}
+
+func (w *snapshotWriter) uvarint(v uint64) {
+	if w.err == nil {
+		_, w.err = w.w.Write(binary.AppendUvarint(w.buf[:0], v))
+	}
+}
+
+func (w *snapshotWriter) varint(v int64) {
+	if w.err == nil {
+		_, w.err = w.w.Write(binary.AppendVarint(w.buf[:0], v))
+	}
+}
+
+func (w *snapshotWriter) marshal(b []byte, err error) {
+	if w.err == nil {
+		w.err = err
+	}
+	w.uvarint(uint64(len(b)))
+	if w.err == nil {
+		_, w.err = w.w.Write(b)
+	}
+}
+
+type snapshotReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *snapshotReader) fail(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	r.err = fmt.Errorf("%w: %w", errSnapshot, err)
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		r.fail(err)
+	}
+	return v
+}
+
+func (r *snapshotReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(r.r)
+	if err != nil {
+		r.fail(err)
+	}
+	return v
+}
+
+func (r *snapshotReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > snapshotMaxLen {
+		r.fail(errors.New("length too large"))
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.fail(err)
+		return nil
+	}
+	return b
+}
+
+// restorable reports whether an item with the given key and weight may be
+// restored to the cache, failing the reader if it may not.
+func restorable[K comparable, V any](r *snapshotReader, c *Cache[K, V], key K, wt int64) bool {
+	if wt <= 0 || wt > c.max {
+		r.fail(errors.New("bad weight"))
+		return false
+	}
+	if _, ok := c.items.tbl[key]; ok {
+		r.fail(errors.New("duplicate key"))
+		return false
+	}
+	return true
+}
+
+func unmarshal[T any](r *snapshotReader, codec Codec[T]) T {
+	var zero T
+	b := r.bytes(r.uvarint())
+	if r.err != nil {
+		return zero
+	}
+	v, err := codec.Unmarshal(b)
+	if err != nil {
+		r.fail(err)
+		return zero
+	}
+	return v
+}
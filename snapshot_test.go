@@ -0,0 +1,187 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"bursavich.dev/arc/arctest"
+)
+
+func TestSnapshot(t *testing.T) {
+	clock := arctest.NewClock(time.Unix(1e9, 0))
+	c := New[int, string](16, WithClock(clock))
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		key := int(rng.Int31n(48))
+		switch rng.Int31n(8) {
+		case 0:
+			c.Delete(key)
+		case 1, 2:
+			c.SetWithTTL(key, strconv.Itoa(key), time.Duration(rng.Int31n(60))*time.Second)
+		default:
+			if _, ok := c.Get(key); !ok {
+				c.Set(key, strconv.Itoa(key))
+			}
+		}
+		clock.Advance(time.Second)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Snapshot(&buf, JSONCodec[int]{}, JSONCodec[string]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	r, err := Restore(&buf, JSONCodec[int]{}, JSONCodec[string]{}, WithClock(clock))
+	if err != nil {
+		t.Fatalf("Restore: unexpected error: %v", err)
+	}
+	if got, want := cacheState(r), cacheState(c); got.String() != want.String() {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	if r.max != c.max || r.pivot != c.pivot {
+		t.Fatalf("unexpected (max, pivot): got: (%d, %d); want: (%d, %d)", r.max, r.pivot, c.max, c.pivot)
+	}
+	if got, want := r.Stats(), c.Stats(); got.Len() != want.Len() || got.Weight() != want.Weight() {
+		t.Fatalf("unexpected (len, weight): got: (%d, %d); want: (%d, %d)", got.Len(), got.Weight(), want.Len(), want.Weight())
+	}
+	for key, i := range c.items.tbl {
+		want := c.items.slots[i]
+		got := r.items.slots[r.items.tbl[key]]
+		if got.key != want.key || got.val != want.val || got.exp != want.exp || got.wt != want.wt || got.list != want.list {
+			t.Fatalf("unexpected item: got: %+v; want: %+v", got, want)
+		}
+	}
+}
+
+func TestSnapshotSync(t *testing.T) {
+	c := NewSync[string, int](4)
+	for i := 0; i < 8; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+	c.Set("5", 5)
+
+	var buf bytes.Buffer
+	if err := c.Snapshot(&buf, JSONCodec[string]{}, JSONCodec[int]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	r, err := RestoreSync(&buf, JSONCodec[string]{}, JSONCodec[int]{})
+	if err != nil {
+		t.Fatalf("RestoreSync: unexpected error: %v", err)
+	}
+	if got, want := cacheState(r.c), cacheState(c.c); got.String() != want.String() {
+		t.Fatalf("unexpected state: got: %v; want: %v", got, want)
+	}
+	if val, ok := r.Get("5"); !ok || val != 5 {
+		t.Fatalf("Get(5): got: (%d, %v); want: (5, true)", val, ok)
+	}
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	c := New[int, int](4)
+	for i := 0; i < 6; i++ {
+		c.Set(i, i)
+	}
+	var buf bytes.Buffer
+	if err := c.Snapshot(&buf, JSONCodec[int]{}, JSONCodec[int]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "header", data: append([]byte("arc\x00"), data[4:]...)},
+		{name: "truncated", data: data[:len(data)-1]},
+		{name: "pivot", data: append([]byte("arc\x01\x04\x05"), data[6:]...)},
+	}
+	for _, tt := range tests {
+		_, err := Restore(bytes.NewReader(tt.data), JSONCodec[int]{}, JSONCodec[int]{})
+		if !errors.Is(err, errSnapshot) {
+			t.Errorf("Restore(%s): unexpected error: got: %v; want: %v", tt.name, err, errSnapshot)
+		}
+	}
+}
+
+func TestRestoreWeigher(t *testing.T) {
+	weigher := WithWeigher(func(key int, val string) int64 { return int64(len(val)) })
+	halved := WithWeigher(func(key int, val string) int64 { return int64(len(val)) / 2 })
+	tests := []struct {
+		name       string
+		opts       []Option
+		live, dead int64
+	}{
+		{name: "same", opts: []Option{weigher}, live: 8, dead: 4},
+		{name: "other", opts: []Option{halved}, live: 4, dead: 4},
+		{name: "none", live: 4, dead: 2},
+	}
+	c := New[int, string](8, weigher)
+	for i := 0; i < 6; i++ {
+		c.Set(i, "xx")
+	}
+	var buf bytes.Buffer
+	if err := c.Snapshot(&buf, JSONCodec[int]{}, JSONCodec[string]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	for _, tt := range tests {
+		r, err := Restore(bytes.NewReader(buf.Bytes()), JSONCodec[int]{}, JSONCodec[string]{}, tt.opts...)
+		if err != nil {
+			t.Fatalf("Restore(%s): unexpected error: %v", tt.name, err)
+		}
+		if got, want := cacheState(r), cacheState(c); got.String() != want.String() {
+			t.Fatalf("Restore(%s): unexpected state: got: %v; want: %v", tt.name, got, want)
+		}
+		if live, dead := r.items.liveWeight(), r.items.deadWeight(); live != tt.live || dead != tt.dead {
+			t.Fatalf("Restore(%s): unexpected (live, dead) weight: got: (%d, %d); want: (%d, %d)", tt.name, live, dead, tt.live, tt.dead)
+		}
+	}
+
+	// The live items may weigh too much to restore.
+	c = New[int, string](4)
+	for i := 0; i < 4; i++ {
+		c.Set(i, "xx")
+	}
+	buf.Reset()
+	if err := c.Snapshot(&buf, JSONCodec[int]{}, JSONCodec[string]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	if _, err := Restore(&buf, JSONCodec[int]{}, JSONCodec[string]{}, weigher); !errors.Is(err, errSnapshot) {
+		t.Fatalf("Restore: unexpected error: got: %v; want: %v", err, errSnapshot)
+	}
+}
+
+func TestRestoreLarge(t *testing.T) {
+	// A weighted cache's size is a budget, which may be larger than any count.
+	const size = 1 << 31
+	weigher := WithWeigher(func(key int, val string) int64 { return int64(len(val)) << 20 })
+	c := New[int, string](size, weigher)
+	c.Set(1, "one")
+	var buf bytes.Buffer
+	if err := c.Snapshot(&buf, JSONCodec[int]{}, JSONCodec[string]{}); err != nil {
+		t.Fatalf("Snapshot: unexpected error: %v", err)
+	}
+	r, err := Restore(&buf, JSONCodec[int]{}, JSONCodec[string]{}, weigher)
+	if err != nil {
+		t.Fatalf("Restore: unexpected error: %v", err)
+	}
+	if r.max != size || r.Stats().Weight() != 3<<20 {
+		t.Fatalf("unexpected (max, weight): got: (%d, %d); want: (%d, %d)", r.max, r.Stats().Weight(), size, 3<<20)
+	}
+
+	// An unweighted cache doesn't preallocate room for the items of a large size.
+	r2, err := Restore(bytes.NewReader([]byte("arc\x01\x80\x80\x80\x80\x80\x01\x00\x00\x00\x00\x00")), JSONCodec[int]{}, JSONCodec[int]{})
+	if err != nil {
+		t.Fatalf("Restore: unexpected error: %v", err)
+	}
+	if got, want := cap(r2.items.slots), numLists+2*snapshotMaxHint; got > want {
+		t.Fatalf("unexpected preallocated slots: got: %d; want: <= %d", got, want)
+	}
+}
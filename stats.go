@@ -0,0 +1,165 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Stats are statistics about a cache.
+type Stats struct {
+	MRUHits      uint64 // Gets of live keys that had been used once recently
+	MFUHits      uint64 // Gets of live keys that had been used more than once recently
+	Misses       uint64 // Gets of keys that were not live
+	MRUGhostHits uint64 // Sets of keys in the dead MRU list
+	MFUGhostHits uint64 // Sets of keys in the dead MFU list
+	Promotions   uint64 // live keys moved from the MRU list to the MFU list
+	Evictions    uint64 // live keys evicted to make room
+	Expirations  uint64 // live keys removed because they expired
+	Deletions    uint64 // live keys deleted
+
+	Max         int64 // max live size, or total weight with a Weigher
+	Pivot       int64 // target size, or total weight, of the live MRU list
+	MRULen      int   // size of the live MRU list
+	MFULen      int   // size of the live MFU list
+	MRUGhostLen int   // size of the dead MRU list
+	MFUGhostLen int   // size of the dead MFU list
+
+	MRUWeight      int64 // total weight of the live MRU list
+	MFUWeight      int64 // total weight of the live MFU list
+	MRUGhostWeight int64 // total weight of the dead MRU list
+	MFUGhostWeight int64 // total weight of the dead MFU list
+}
+
+// Hits returns the number of Gets of live keys.
+func (s Stats) Hits() uint64 {
+	return s.MRUHits + s.MFUHits
+}
+
+// GhostHits returns the number of Sets of dead keys.
+func (s Stats) GhostHits() uint64 {
+	return s.MRUGhostHits + s.MFUGhostHits
+}
+
+// HitRatio returns the fraction of Gets that found live keys.
+func (s Stats) HitRatio() float64 {
+	hits := s.Hits()
+	if total := hits + s.Misses; total > 0 {
+		return float64(hits) / float64(total)
+	}
+	return 0
+}
+
+// Len returns the number of live keys.
+func (s Stats) Len() int {
+	return s.MRULen + s.MFULen
+}
+
+// Weight returns the total weight of live values.
+// Without a Weigher, each value weighs one.
+func (s Stats) Weight() int64 {
+	return s.MRUWeight + s.MFUWeight
+}
+
+func (s *Stats) add(o *Stats) {
+	s.MRUHits += o.MRUHits
+	s.MFUHits += o.MFUHits
+	s.Misses += o.Misses
+	s.MRUGhostHits += o.MRUGhostHits
+	s.MFUGhostHits += o.MFUGhostHits
+	s.Promotions += o.Promotions
+	s.Evictions += o.Evictions
+	s.Expirations += o.Expirations
+	s.Deletions += o.Deletions
+	s.Max += o.Max
+	s.Pivot += o.Pivot
+	s.MRULen += o.MRULen
+	s.MFULen += o.MFULen
+	s.MRUGhostLen += o.MRUGhostLen
+	s.MFUGhostLen += o.MFUGhostLen
+	s.MRUWeight += o.MRUWeight
+	s.MFUWeight += o.MFUWeight
+	s.MRUGhostWeight += o.MRUGhostWeight
+	s.MFUGhostWeight += o.MFUGhostWeight
+}
+
+// stats are atomically updated so they may be read from any goroutine.
+type stats struct {
+	hits        [2]atomic.Uint64 // by Segment
+	misses      atomic.Uint64
+	ghostHits   [2]atomic.Uint64 // by Segment
+	promotions  atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+	deletions   atomic.Uint64
+
+	seq          atomic.Uint64 // odd while the cache is storing the gauges below
+	max          atomic.Int64
+	pivot        atomic.Int64
+	lens         [2]atomic.Int64 // by Segment
+	ghostLen     [2]atomic.Int64 // by Segment
+	weights      [2]atomic.Int64 // by Segment
+	ghostWeights [2]atomic.Int64 // by Segment
+}
+
+func (s *stats) load() Stats {
+	st := Stats{
+		MRUHits:      s.hits[MRU].Load(),
+		MFUHits:      s.hits[MFU].Load(),
+		Misses:       s.misses.Load(),
+		MRUGhostHits: s.ghostHits[MRU].Load(),
+		MFUGhostHits: s.ghostHits[MFU].Load(),
+		Promotions:   s.promotions.Load(),
+		Evictions:    s.evictions.Load(),
+		Expirations:  s.expirations.Load(),
+		Deletions:    s.deletions.Load(),
+	}
+	// Retry until the gauges are loaded without being stored in the meantime.
+	for {
+		seq := s.seq.Load()
+		if seq&1 != 0 {
+			runtime.Gosched()
+			continue
+		}
+		st.Max = s.max.Load()
+		st.Pivot = s.pivot.Load()
+		st.MRULen = int(s.lens[MRU].Load())
+		st.MFULen = int(s.lens[MFU].Load())
+		st.MRUGhostLen = int(s.ghostLen[MRU].Load())
+		st.MFUGhostLen = int(s.ghostLen[MFU].Load())
+		st.MRUWeight = s.weights[MRU].Load()
+		st.MFUWeight = s.weights[MFU].Load()
+		st.MRUGhostWeight = s.ghostWeights[MRU].Load()
+		st.MFUGhostWeight = s.ghostWeights[MFU].Load()
+		if s.seq.Load() == seq {
+			return st
+		}
+	}
+}
+
+// Stats returns statistics about the cache.
+// It is safe to call concurrently with other operations.
+//
+// The max, pivot, lengths, and weights are loaded together, so they describe
+// the same state of the cache. Each counter is loaded independently.
+func (c *Cache[K, V]) Stats() Stats {
+	return c.stats.load()
+}
+
+// Stats returns statistics about the cache.
+func (c *SyncCache[K, V]) Stats() Stats {
+	return c.c.Stats()
+}
+
+// Stats returns statistics about the cache, summed across its shards.
+func (c *ShardedCache[K, V]) Stats() Stats {
+	var s Stats
+	for _, shard := range c.shards {
+		st := shard.Stats()
+		s.add(&st)
+	}
+	return s
+}
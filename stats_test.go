@@ -0,0 +1,93 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bursavich.dev/arc/arctest"
+)
+
+func TestStats(t *testing.T) {
+	clock := arctest.NewClock(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := New[int, int](2, WithClock(clock))
+	c.Set(0, 0)                     // [ ... 0 | ... ]
+	c.Set(1, 1)                     // [ ... 0, 1 | ... ]
+	c.Get(0)                        // [ ... 1 | 0 ... ]
+	c.Get(0)                        // [ ... 1 | 0 ... ]
+	c.Get(1)                        // [ ... | 1, 0 ... ]
+	c.Get(2)                        // [ ... | 1, 0 ... ]
+	c.Set(2, 2)                     // [ ... 2 | 1 ... 0 ]
+	c.Set(0, 0)                     // [ 2 ... | 0, 1 ... ]
+	c.Set(3, 3)                     // [ 2 ... 3 | 0 ... 1 ]
+	c.Set(2, 2)                     // [ ... 3 | 2 ... 0, 1 ]
+	c.Delete(0)                     // [ ... 3 | 2 ... 1 ]
+	c.Delete(3)                     // [ ... | 2 ... 1 ]
+	c.SetWithTTL(4, 4, time.Second) // [ ... 4 | 2 ... 1 ]
+	clock.Advance(time.Second)
+	c.Get(4) // [ ... | 2 ... 1 ]
+
+	want := Stats{
+		MRUHits:      2,
+		MFUHits:      1,
+		Misses:       2,
+		MRUGhostHits: 1,
+		MFUGhostHits: 1,
+		Promotions:   2,
+		Evictions:    4,
+		Expirations:  1,
+		Deletions:    1,
+		Max:          2,
+		Pivot:        1,
+		MRULen:       0,
+		MFULen:       1,
+		MRUGhostLen:  0,
+		MFUGhostLen:  1,
+
+		MRUWeight:      0,
+		MFUWeight:      1,
+		MRUGhostWeight: 0,
+		MFUGhostWeight: 1,
+	}
+	if got := c.Stats(); got != want {
+		t.Fatalf("unexpected stats:\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if got, want := want.HitRatio(), 0.6; got != want {
+		t.Fatalf("unexpected hit ratio: got: %v; want: %v", got, want)
+	}
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	c := New[int, int](16)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				if s := c.Stats(); s.Weight() > s.Max {
+					t.Errorf("unexpected weight: %d; max: %d", s.Weight(), s.Max)
+					return
+				}
+			}
+		}
+	}()
+	for i := 0; i < 10000; i++ {
+		if i%100 == 0 {
+			c.Resize(8 + i/100%2*8)
+		}
+		if _, ok := c.Get(i % 64); !ok {
+			c.Set(i%64, i)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
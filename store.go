@@ -0,0 +1,165 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import "math"
+
+// The lists of a store. The sentinel of each list is the slot with the same index.
+const (
+	liveMRU  = iota // live items used once recently
+	liveMFU         // live items used more than once recently
+	deadMRU         // keys evicted from liveMRU
+	deadMFU         // keys evicted from liveMFU
+	freeList        // unused slots
+	numLists
+)
+
+// maxSlots is the max number of slots in a store, including sentinels.
+const maxSlots = math.MaxInt32
+
+// liveList returns the list of live items in the segment.
+func liveList(seg Segment) int32 {
+	checkSegment(seg)
+	return liveMRU + int32(seg)
+}
+
+// deadList returns the list of dead items in the segment.
+func deadList(seg Segment) int32 {
+	checkSegment(seg)
+	return deadMRU + int32(seg)
+}
+
+// A slot holds an item in a store and links it to its neighbors by index.
+type slot[K comparable, V any] struct {
+	key        K
+	val        V     // zero if dead
+	exp        int64 // expiration in unix nanoseconds, or zero if none
+	wt         int64 // weight
+	prev, next int32
+	list       int32
+}
+
+// seg returns the segment of the slot's list.
+func (s *slot[K, V]) seg() Segment {
+	return Segment(s.list & 1)
+}
+
+// A store holds the live and dead items of a cache in a slice of slots,
+// which are linked into lists by index rather than by pointer. Unless the
+// keys or values contain pointers, neither the slots nor the table does,
+// so the garbage collector needn't scan them. Freed slots are reused.
+//
+// Pointers to slots are invalidated by push.
+type store[K comparable, V any] struct {
+	tbl   map[K]int32     // slot by key, for live and dead items
+	slots []slot[K, V]    // sentinels, followed by items and free slots
+	lens  [numLists]int   // number of slots by list
+	wt    [numLists]int64 // total weight by list
+}
+
+// init initializes the store with room for size live items and size dead items.
+func (s *store[K, V]) init(size int) {
+	size = int(min(int64(size), (maxSlots-numLists)/2))
+	s.tbl = make(map[K]int32, 2*size)
+	s.slots = make([]slot[K, V], numLists, numLists+2*size)
+	s.reset()
+}
+
+// reset frees all of the slots.
+func (s *store[K, V]) reset() {
+	clear(s.tbl)
+	clear(s.slots[numLists:])
+	s.slots = s.slots[:numLists]
+	for l := range int32(numLists) {
+		s.slots[l] = slot[K, V]{prev: l, next: l, list: l}
+	}
+	s.lens = [numLists]int{}
+	s.wt = [numLists]int64{}
+}
+
+// liveWeight returns the total weight of the live items.
+func (s *store[K, V]) liveWeight() int64 {
+	return s.wt[liveMRU] + s.wt[liveMFU]
+}
+
+// deadWeight returns the total weight of the dead items.
+func (s *store[K, V]) deadWeight() int64 {
+	return s.wt[deadMRU] + s.wt[deadMFU]
+}
+
+// liveLen returns the number of live items.
+func (s *store[K, V]) liveLen() int {
+	return s.lens[liveMRU] + s.lens[liveMFU]
+}
+
+// front returns the first slot of list l, which is l itself if the list is empty.
+func (s *store[K, V]) front(l int32) int32 {
+	return s.slots[l].next
+}
+
+// back returns the last slot of list l, which is l itself if the list is empty.
+func (s *store[K, V]) back(l int32) int32 {
+	return s.slots[l].prev
+}
+
+// push inserts the item at the front of list l and returns its slot.
+func (s *store[K, V]) push(l int32, key K, val V, exp, wt int64) int32 {
+	i := s.front(freeList)
+	if i != freeList {
+		s.unlink(i)
+	} else {
+		if len(s.slots) == maxSlots {
+			panic("arc: too many items")
+		}
+		i = int32(len(s.slots))
+		s.slots = append(s.slots, slot[K, V]{})
+	}
+	p := &s.slots[i]
+	p.key = key
+	p.val = val
+	p.exp = exp
+	p.wt = wt
+	s.link(i, l)
+	s.tbl[key] = i
+	return i
+}
+
+// move moves slot i to the front of list l.
+func (s *store[K, V]) move(i, l int32) {
+	s.unlink(i)
+	s.link(i, l)
+}
+
+// remove removes the item in slot i from the store, frees the slot, and returns the item.
+func (s *store[K, V]) remove(i int32) slot[K, V] {
+	it := s.slots[i]
+	delete(s.tbl, it.key)
+	s.unlink(i)
+	s.slots[i] = slot[K, V]{} // don't retain the key or value
+	s.link(i, freeList)
+	return it
+}
+
+// link inserts slot i at the front of list l.
+func (s *store[K, V]) link(i, l int32) {
+	p := &s.slots[i]
+	next := s.slots[l].next
+	p.prev = l
+	p.next = next
+	p.list = l
+	s.slots[next].prev = i
+	s.slots[l].next = i
+	s.lens[l]++
+	s.wt[l] += p.wt
+}
+
+// unlink removes slot i from its list.
+func (s *store[K, V]) unlink(i int32) {
+	p := &s.slots[i]
+	s.slots[p.prev].next = p.next
+	s.slots[p.next].prev = p.prev
+	s.lens[p.list]--
+	s.wt[p.list] -= p.wt
+}
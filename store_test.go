@@ -0,0 +1,134 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+
+	"bursavich.dev/arc/internal/list"
+)
+
+func TestStoreReuse(t *testing.T) {
+	const size = 100
+	c := New[int, string](size, WithTTL(time.Hour))
+	want := cap(c.items.slots)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100*size; i++ {
+		key := rng.Intn(4 * size)
+		switch rng.Intn(10) {
+		case 0:
+			c.Delete(key)
+		case 1, 2, 3:
+			c.Set(key, "")
+		default:
+			c.Get(key)
+		}
+	}
+	if got := cap(c.items.slots); got != want {
+		t.Fatalf("unexpected slot capacity: got: %d; want: %d", got, want)
+	}
+	var free int
+	for i := c.items.front(freeList); i != freeList; i = c.items.slots[i].next {
+		free++
+	}
+	if got, want := c.items.lens[freeList], free; got != want {
+		t.Fatalf("unexpected free slots: got: %d; want: %d", got, want)
+	}
+	if got, want := numLists+len(c.items.tbl)+free, len(c.items.slots); got != want {
+		t.Fatalf("unexpected slots: got: %d; want: %d", got, want)
+	}
+
+	c.Purge()
+	if got, want := len(c.items.slots), numLists; got != want {
+		t.Fatalf("unexpected slots after Purge: got: %d; want: %d", got, want)
+	}
+}
+
+// ptrItem and ptrSubCache reproduce the storage that Cache used before store:
+// a table and linked lists of pointers for each of the live and dead items.
+type ptrItem[K comparable, V any] struct {
+	key K
+	val V
+	hot bool
+	exp int64
+	wt  int64
+}
+
+type ptrSubCache[K comparable, V any] struct {
+	tbl map[K]*list.Element[ptrItem[K, V]]
+	mru list.List[ptrItem[K, V]]
+	mfu list.List[ptrItem[K, V]]
+	wt  [2]int64 // total weight by Segment
+}
+
+func (c *ptrSubCache[K, V]) init(size int) {
+	c.tbl = make(map[K]*list.Element[ptrItem[K, V]], size)
+}
+
+func (c *ptrSubCache[K, V]) push(it ptrItem[K, V]) {
+	l := &c.mru
+	if it.hot {
+		l = &c.mfu
+	}
+	c.tbl[it.key] = l.PushFront(it)
+}
+
+// BenchmarkGC reports the time of a full garbage collection, its pause time,
+// and the memory per entry of storage filled with size live and size dead entries,
+// both for store and for the linked lists of pointers that it replaced.
+func BenchmarkGC(b *testing.B) {
+	layouts := []struct {
+		name string
+		fill func(size int) any
+	}{
+		{"Store", func(size int) any {
+			var s store[int, int]
+			s.init(size)
+			for i := 0; i < size; i++ {
+				s.push(liveMFU, i, i, 0, 1)
+				s.push(deadMFU, size+i, 0, 0, 1)
+			}
+			return &s
+		}},
+		{"Pointers", func(size int) any {
+			var live, dead ptrSubCache[int, int]
+			live.init(size)
+			dead.init(size)
+			for i := 0; i < size; i++ {
+				live.push(ptrItem[int, int]{key: i, val: i, hot: true, wt: 1})
+				dead.push(ptrItem[int, int]{key: size + i, hot: true, wt: 1})
+			}
+			return []*ptrSubCache[int, int]{&live, &dead}
+		}},
+	}
+	for _, size := range []int{1 << 16, 1 << 20} {
+		for _, tt := range layouts {
+			b.Run(fmt.Sprintf("%s/size=%d", tt.name, size), func(b *testing.B) {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				v := tt.fill(size)
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				perEntry := float64(after.HeapAlloc-before.HeapAlloc) / float64(2*size)
+
+				runtime.ReadMemStats(&before)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					runtime.GC()
+				}
+				b.StopTimer()
+				runtime.ReadMemStats(&after)
+				b.ReportMetric(perEntry, "B/entry")
+				b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(after.NumGC-before.NumGC), "pause-ns/gc")
+				runtime.KeepAlive(v)
+			})
+		}
+	}
+}
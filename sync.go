@@ -0,0 +1,116 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"sync"
+	"time"
+)
+
+// SyncCache is an adaptive replacement cache.
+// It is safe for concurrent access.
+//
+// Every operation, including Get, reorders the cache's internal lists,
+// so all access is serialized by a single mutex.
+type SyncCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	c     *Cache[K, V]
+	calls map[K]*call[V] // in-flight loads
+}
+
+// NewSync creates a new SyncCache.
+func NewSync[K comparable, V any](size int, opts ...Option) *SyncCache[K, V] {
+	return &SyncCache[K, V]{c: New[K, V](size, opts...)}
+}
+
+// Len returns the number of live items in the cache.
+// It includes expired items that have not yet been removed.
+func (c *SyncCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Len()
+}
+
+// Get reads the key's value from the cache.
+func (c *SyncCache[K, V]) Get(key K) (value V, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Get(key)
+}
+
+// Clear removes all live items from the cache.
+// See Cache.Clear for details.
+func (c *SyncCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.calls)
+	c.c.Clear()
+}
+
+// Purge removes all live and dead items from the cache and resets the pivot.
+// See Cache.Purge for details.
+func (c *SyncCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.calls)
+	c.c.Purge()
+}
+
+// Resize changes the max number of live items in the cache.
+// See Cache.Resize for details.
+func (c *SyncCache[K, V]) Resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.Resize(size)
+}
+
+// Peek reads the key's value from the cache without updating its recency or frequency.
+func (c *SyncCache[K, V]) Peek(key K) (value V, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Peek(key)
+}
+
+// Contains reports whether the key's value is in the cache
+// without updating its recency or frequency.
+func (c *SyncCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Contains(key)
+}
+
+// WasRecentlyEvicted reports whether the key's value was recently evicted
+// and the key remains in the dead cache, where setting it again would adapt the pivot.
+func (c *SyncCache[K, V]) WasRecentlyEvicted(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.WasRecentlyEvicted(key)
+}
+
+// Set writes the key's value to the cache.
+// It expires after the cache's default time-to-live, if any.
+func (c *SyncCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(key)
+	c.c.Set(key, value)
+}
+
+// SetWithTTL writes the key's value to the cache.
+// It expires after the given time-to-live, or never if ttl is not positive.
+func (c *SyncCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(key)
+	c.c.SetWithTTL(key, value, ttl)
+}
+
+// Delete deletes the key's value from the cache.
+func (c *SyncCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(key)
+	c.c.Delete(key)
+}
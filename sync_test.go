@@ -0,0 +1,58 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSyncConcurrent(t *testing.T) {
+	const (
+		size       = 64
+		keys       = size * 4
+		goroutines = 16
+		ops        = 10000
+	)
+	c := NewSync[int, string](size)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				key := rng.Intn(keys)
+				switch n := rng.Intn(20); {
+				case n < 10:
+					if val, ok := c.Get(key); ok && val != strconv.Itoa(key) {
+						t.Errorf("Get(%d): unexpected value: %q", key, val)
+						return
+					}
+				case n < 18:
+					c.Set(key, strconv.Itoa(key))
+				case n < 19:
+					c.Delete(key)
+				default:
+					if n := c.Len(); n > size {
+						t.Errorf("Len(): got: %d; want <= %d", n, size)
+						return
+					}
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	s := cacheState(c.c)
+	if n := len(s[1]) + len(s[2]); n != c.Len() || n > size {
+		t.Fatalf("unexpected live size: %d; Len: %d; max: %d", n, c.Len(), size)
+	}
+	if n := len(s[0]) + len(s[3]); n > size {
+		t.Fatalf("unexpected dead size: %d; max: %d", n, size)
+	}
+}
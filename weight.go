@@ -0,0 +1,21 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+// A Weigher returns the weight of a key's value, such as its size in bytes.
+// Weights must be greater than zero. It must not access the cache.
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// WithWeigher returns an Option that bounds the cache by the total weight of its values,
+// rather than by their number. The size given to the cache's constructor becomes the max
+// total weight of its live values, and the pivot and dead lists are measured by weight too,
+// so that adaptation remains proportional. A value heavier than the max total weight is not
+// cached, and the cache's EvictFunc, if any, is called with EvictRejected.
+// The weigher's key and value types must match those of the cache.
+func WithWeigher[K comparable, V any](fn Weigher[K, V]) Option {
+	return optionFunc(func(o *options) {
+		o.weigher = fn
+	})
+}
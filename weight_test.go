@@ -0,0 +1,115 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package arc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWeigher(t *testing.T) {
+	type event struct {
+		key   int
+		val   int
+		cause EvictCause
+	}
+	var events []event
+	tests := []struct {
+		cmd    string
+		key    int
+		val    int // value and weight
+		pivot  int64
+		weight [2]int64 // live and dead
+		events []event
+		state  state[int]
+	}{
+		{cmd: "set", key: 0, val: 3, pivot: 5, weight: [2]int64{3, 0}, state: state[int]{{}, {0}, {}, {}}},
+		{cmd: "set", key: 1, val: 3, pivot: 5, weight: [2]int64{6, 0}, state: state[int]{{}, {0, 1}, {}, {}}},
+		{cmd: "set", key: 2, val: 3, pivot: 5, weight: [2]int64{9, 0}, state: state[int]{{}, {0, 1, 2}, {}, {}}},
+		{cmd: "set", key: 3, val: 2, pivot: 5, weight: [2]int64{8, 3}, events: []event{{0, 3, EvictCapacity}}, state: state[int]{{0}, {1, 2, 3}, {}, {}}},
+		{cmd: "get", key: 1, val: 3, pivot: 5, weight: [2]int64{8, 3}, state: state[int]{{0}, {2, 3}, {1}, {}}},
+		{cmd: "set", key: 4, val: 4, pivot: 5, weight: [2]int64{9, 6}, events: []event{{1, 3, EvictCapacity}}, state: state[int]{{0}, {2, 3, 4}, {}, {1}}},
+		{cmd: "set", key: 0, val: 3, pivot: 8, weight: [2]int64{9, 6}, events: []event{{2, 3, EvictCapacity}}, state: state[int]{{2}, {3, 4}, {0}, {1}}},
+		{cmd: "set", key: 1, val: 6, pivot: 5, weight: [2]int64{10, 8}, events: []event{{3, 2, EvictCapacity}, {0, 3, EvictCapacity}}, state: state[int]{{2, 3}, {4}, {1}, {0}}},
+		{cmd: "set", key: 5, val: 11, pivot: 5, weight: [2]int64{10, 8}, events: []event{{5, 11, EvictRejected}}, state: state[int]{{2, 3}, {4}, {1}, {0}}},
+		{cmd: "set", key: 2, val: 1, pivot: 8, weight: [2]int64{5, 8}, events: []event{{1, 6, EvictCapacity}}, state: state[int]{{3}, {4}, {2}, {1}}},
+		{cmd: "set", key: 2, val: 5, pivot: 8, weight: [2]int64{9, 8}, events: []event{{2, 1, EvictReplaced}}, state: state[int]{{3}, {4}, {2}, {1}}},
+		{cmd: "set", key: 2, val: 7, pivot: 8, weight: [2]int64{7, 6}, events: []event{{4, 4, EvictCapacity}, {2, 5, EvictReplaced}}, state: state[int]{{3, 4}, {}, {2}, {}}},
+		{cmd: "set", key: 6, val: 3, pivot: 8, weight: [2]int64{10, 6}, state: state[int]{{3, 4}, {6}, {2}, {}}},
+		{cmd: "set", key: 3, val: 2, pivot: 10, weight: [2]int64{5, 4}, events: []event{{2, 7, EvictCapacity}}, state: state[int]{{4}, {6}, {3}, {}}},
+		{cmd: "set", key: 6, val: 11, pivot: 10, weight: [2]int64{2, 4}, events: []event{{6, 3, EvictReplaced}, {6, 11, EvictRejected}}, state: state[int]{{4}, {}, {3}, {}}},
+		{cmd: "del", key: 3, pivot: 10, weight: [2]int64{0, 4}, events: []event{{3, 2, EvictDeleted}}, state: state[int]{{4}, {}, {}, {}}},
+	}
+	c := New[int, int](10,
+		WithWeigher(func(key, val int) int64 { return int64(val) }),
+		WithEvictFunc(func(key, val int, cause EvictCause) {
+			events = append(events, event{key, val, cause})
+		}),
+	)
+	for i, tt := range tests {
+		events = nil
+		var cmd string
+		switch tt.cmd {
+		case "get":
+			cmd = fmt.Sprintf("Get(%d)", tt.key)
+			if val, _ := c.Get(tt.key); tt.val != val {
+				t.Fatalf("step %d: %s: unexpected value; got: %d; want: %d", i, cmd, val, tt.val)
+			}
+		case "set":
+			cmd = fmt.Sprintf("Set(%d, %d)", tt.key, tt.val)
+			c.Set(tt.key, tt.val)
+		case "del":
+			cmd = fmt.Sprintf("Delete(%d)", tt.key)
+			c.Delete(tt.key)
+		default:
+			t.Fatalf("step %d: unexpected command: %q", i, tt.cmd)
+		}
+		if got := cacheState(c); !reflect.DeepEqual(got, tt.state) {
+			var prev state[int]
+			if i > 0 {
+				prev = tests[i-1].state
+			}
+			t.Fatalf("step %d: %s: unexpected state:\nprev %s\ngot  %s\nwant %s", i, cmd, prev, got, tt.state)
+		}
+		if c.pivot != tt.pivot {
+			t.Fatalf("step %d: %s: unexpected pivot; got: %d; want: %d", i, cmd, c.pivot, tt.pivot)
+		}
+		if got := [2]int64{c.items.liveWeight(), c.items.deadWeight()}; got != tt.weight {
+			t.Fatalf("step %d: %s: unexpected weight; got: %v; want: %v", i, cmd, got, tt.weight)
+		}
+		if !reflect.DeepEqual(events, tt.events) {
+			t.Fatalf("step %d: %s: unexpected events; got: %v; want: %v", i, cmd, events, tt.events)
+		}
+	}
+	if s := c.Stats(); s.Weight() != 0 || s.MRUGhostWeight+s.MFUGhostWeight != 4 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
+
+func TestWeigherInvalid(t *testing.T) {
+	c := New[int, int](10, WithWeigher(func(key, val int) int64 { return int64(val) }))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	c.Set(0, 0)
+}
+
+func TestWeigherRejected(t *testing.T) {
+	var events []EvictCause
+	c := New[int, int](10,
+		WithWeigher(func(key, val int) int64 { return int64(val) }),
+		WithEvictFunc(func(key, val int, cause EvictCause) { events = append(events, cause) }),
+	)
+	c.Set(0, 11)
+	if want := []EvictCause{EvictRejected}; !reflect.DeepEqual(events, want) {
+		t.Fatalf("unexpected events: got: %v; want: %v", events, want)
+	}
+	if s := c.Stats(); s.Evictions != 0 || c.Len() != 0 {
+		t.Fatalf("unexpected (evictions, len): got: (%d, %d); want: (0, 0)", s.Evictions, c.Len())
+	}
+}
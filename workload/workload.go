@@ -0,0 +1,222 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+// Package workload generates synthetic streams of keys for benchmarks,
+// simulations, and capacity planning.
+//
+// Every generator that makes random choices takes a seed,
+// so the same seed always produces the same stream.
+package workload
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A Generator produces a stream of keys.
+// Generators are not safe for concurrent access.
+type Generator interface {
+	// Next returns the next key in the stream.
+	Next() uint64
+}
+
+// Take returns the next n keys from the generator.
+func Take(g Generator, n int) []uint64 {
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = g.Next()
+	}
+	return keys
+}
+
+type uniform struct {
+	rng *rand.Rand
+	n   uint64
+}
+
+// Uniform returns a Generator of keys chosen uniformly from [0, n).
+func Uniform(seed int64, n uint64) Generator {
+	checkKeys(n)
+	return &uniform{rng: rand.New(rand.NewSource(seed)), n: n}
+}
+
+func (g *uniform) Next() uint64 {
+	return uint64(g.rng.Int63n(int64(g.n)))
+}
+
+type zipf struct {
+	rng   *rand.Rand
+	n     float64
+	theta float64
+	half  float64 // 1 + 0.5^theta
+	alpha float64
+	zetaN float64
+	eta   float64
+}
+
+// Zipf returns a Generator of keys from [0, n) with a Zipfian distribution,
+// in which key i is chosen with probability proportional to 1/(i+1)^theta.
+// The skew, theta, must be between 0 and 1, exclusive, and is typically 0.99.
+//
+// Construction takes time proportional to n. Sampling takes constant time,
+// using the method of Gray et al., "Quickly Generating Billion-Record Synthetic Databases".
+func Zipf(seed int64, n uint64, theta float64) Generator {
+	checkKeys(n)
+	if !(theta > 0 && theta < 1) {
+		panic("workload: theta must be between 0 and 1")
+	}
+	zetaN := zeta(n, theta)
+	return &zipf{
+		rng:   rand.New(rand.NewSource(seed)),
+		n:     float64(n),
+		theta: theta,
+		half:  1 + math.Pow(0.5, theta),
+		alpha: 1 / (1 - theta),
+		zetaN: zetaN,
+		eta:   (1 - math.Pow(2/float64(n), 1-theta)) / (1 - zeta(2, theta)/zetaN),
+	}
+}
+
+func (g *zipf) Next() uint64 {
+	u := g.rng.Float64()
+	uz := u * g.zetaN
+	switch {
+	case uz < 1:
+		return 0
+	case uz < g.half && g.n > 1:
+		return 1
+	}
+	k := uint64(g.n * math.Pow(g.eta*u-g.eta+1, g.alpha))
+	return min(k, uint64(g.n)-1)
+}
+
+func zeta(n uint64, theta float64) float64 {
+	var sum float64
+	for i := uint64(1); i <= n; i++ {
+		sum += 1 / math.Pow(float64(i), theta)
+	}
+	return sum
+}
+
+type scan struct {
+	next uint64
+}
+
+// Scan returns a Generator of sequential keys beginning at start,
+// none of which repeat.
+func Scan(start uint64) Generator {
+	return &scan{next: start}
+}
+
+func (g *scan) Next() uint64 {
+	k := g.next
+	g.next++
+	return k
+}
+
+type loop struct {
+	i, n uint64
+}
+
+// Loop returns a Generator that repeatedly loops over the keys in [0, n) in order.
+func Loop(n uint64) Generator {
+	checkKeys(n)
+	return &loop{n: n}
+}
+
+func (g *loop) Next() uint64 {
+	k := g.i
+	if g.i++; g.i == g.n {
+		g.i = 0
+	}
+	return k
+}
+
+type hotSet struct {
+	rng    *rand.Rand
+	n, hot uint64
+	period int
+	count  int
+	offset uint64
+}
+
+// HotSet returns a Generator of keys chosen uniformly from a hot set of hot keys,
+// which shifts to the next hot keys in [0, n) after every period keys,
+// wrapping around at n.
+func HotSet(seed int64, n, hot uint64, period int) Generator {
+	checkKeys(n)
+	if hot == 0 || hot > n {
+		panic("workload: hot set size must be greater than 0 and not greater than keys")
+	}
+	if period <= 0 {
+		panic("workload: period must be greater than 0")
+	}
+	return &hotSet{rng: rand.New(rand.NewSource(seed)), n: n, hot: hot, period: period}
+}
+
+func (g *hotSet) Next() uint64 {
+	if g.count == g.period {
+		g.count = 0
+		g.offset = (g.offset + g.hot) % g.n
+	}
+	g.count++
+	return (g.offset + uint64(g.rng.Int63n(int64(g.hot)))) % g.n
+}
+
+type mix struct {
+	rng  *rand.Rand
+	gens []Generator
+	cum  []float64 // cumulative weights
+}
+
+// Mix returns a Generator that chooses each key from one of the generators,
+// picked at random in proportion to its weight.
+func Mix(seed int64, gens []Generator, weights []float64) Generator {
+	if len(gens) == 0 || len(gens) != len(weights) {
+		panic("workload: must have one weight per generator")
+	}
+	cum := make([]float64, len(weights))
+	var sum float64
+	for i, w := range weights {
+		if w < 0 {
+			panic("workload: weights must not be negative")
+		}
+		sum += w
+		cum[i] = sum
+	}
+	if sum == 0 {
+		panic("workload: weights must not all be zero")
+	}
+	return &mix{rng: rand.New(rand.NewSource(seed)), gens: gens, cum: cum}
+}
+
+func (g *mix) Next() uint64 {
+	r := g.rng.Float64() * g.cum[len(g.cum)-1]
+	for i, c := range g.cum {
+		if r < c {
+			return g.gens[i].Next()
+		}
+	}
+	return g.gens[len(g.gens)-1].Next()
+}
+
+// ScanHot returns a Generator that mixes a Zipfian hot set of hot keys with
+// a sequential scan of keys that never repeat. The scan makes up the given
+// fraction of the keys. It's the kind of workload in which a scan flushes
+// useful keys from an LRU cache, but not from an adaptive replacement cache.
+func ScanHot(seed int64, hot uint64, scanFraction float64) Generator {
+	if !(scanFraction >= 0 && scanFraction <= 1) {
+		panic("workload: scan fraction must be between 0 and 1")
+	}
+	return Mix(seed,
+		[]Generator{Zipf(seed+1, hot, 0.99), Scan(hot)},
+		[]float64{1 - scanFraction, scanFraction},
+	)
+}
+
+func checkKeys(n uint64) {
+	if n == 0 || n > math.MaxInt64 {
+		panic("workload: number of keys must be greater than 0")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2015 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package workload
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bursavich.dev/arc"
+	"bursavich.dev/arc/policy"
+)
+
+func TestDeterministic(t *testing.T) {
+	gens := map[string]func() Generator{
+		"Uniform": func() Generator { return Uniform(1, 100) },
+		"Zipf":    func() Generator { return Zipf(1, 100, 0.99) },
+		"HotSet":  func() Generator { return HotSet(1, 100, 10, 5) },
+		"ScanHot": func() Generator { return ScanHot(1, 100, 0.3) },
+	}
+	for name, newGen := range gens {
+		if a, b := Take(newGen(), 1000), Take(newGen(), 1000); !reflect.DeepEqual(a, b) {
+			t.Errorf("%s: streams with the same seed differ", name)
+		}
+	}
+	if a, b := Take(Uniform(1, 1000), 100), Take(Uniform(2, 1000), 100); reflect.DeepEqual(a, b) {
+		t.Error("Uniform: streams with different seeds are the same")
+	}
+}
+
+func TestScanLoop(t *testing.T) {
+	if got, want := Take(Scan(10), 5), []uint64{10, 11, 12, 13, 14}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan: got: %v; want: %v", got, want)
+	}
+	if got, want := Take(Loop(3), 7), []uint64{0, 1, 2, 0, 1, 2, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Loop: got: %v; want: %v", got, want)
+	}
+}
+
+func TestUniform(t *testing.T) {
+	counts := make([]int, 10)
+	for _, k := range Take(Uniform(1, 10), 100000) {
+		counts[k]++
+	}
+	for k, n := range counts {
+		if n < 9000 || n > 11000 {
+			t.Errorf("key %d: unexpected count: got: %d; want: ~10000", k, n)
+		}
+	}
+}
+
+func TestZipf(t *testing.T) {
+	const n = 1000
+	counts := make([]int, n)
+	for _, k := range Take(Zipf(1, n, 0.99), 100000) {
+		if k >= n {
+			t.Fatalf("key out of range: %d", k)
+		}
+		counts[k]++
+	}
+	// With theta near 1, key i is about i+1 times less popular than key 0.
+	for _, k := range []int{1, 3, 9} {
+		if ratio := float64(counts[0]) / float64(counts[k]); ratio < 0.7*float64(k+1) || ratio > 1.3*float64(k+1) {
+			t.Errorf("key %d: unexpected popularity relative to key 0: got: %.2f; want: ~%d", k, ratio, k+1)
+		}
+	}
+	if Zipf(1, 1, 0.5).Next() != 0 {
+		t.Error("Zipf with one key: unexpected key")
+	}
+}
+
+func TestHotSet(t *testing.T) {
+	g := HotSet(1, 25, 10, 100)
+	for period, lo := range []uint64{0, 10, 20, 5} {
+		for _, k := range Take(g, 100) {
+			if (k+25-lo)%25 >= 10 {
+				t.Fatalf("period %d: key %d outside hot set [%d, %d) mod 25", period, k, lo, lo+10)
+			}
+		}
+	}
+}
+
+func TestMix(t *testing.T) {
+	g := Mix(1, []Generator{Loop(1), Scan(1)}, []float64{3, 1})
+	var zeros int
+	for _, k := range Take(g, 100000) {
+		if k == 0 {
+			zeros++
+		}
+	}
+	if zeros < 74000 || zeros > 76000 {
+		t.Errorf("unexpected count from first generator: got: %d; want: ~75000", zeros)
+	}
+}
+
+func hitRatio(c arc.Policy[uint64, struct{}], keys []uint64) float64 {
+	var hits int
+	for _, k := range keys {
+		if _, ok := c.Get(k); ok {
+			hits++
+		} else {
+			c.Set(k, struct{}{})
+		}
+	}
+	return float64(hits) / float64(len(keys))
+}
+
+// TestScanResistance demonstrates that scans flush an LRU cache,
+// but an adaptive replacement cache retains its frequently used keys.
+func TestScanResistance(t *testing.T) {
+	const size = 500
+	keys := Take(ScanHot(1, 1000, 0.5), 200000)
+	lruRatio := hitRatio(policy.NewLRU[uint64, struct{}](size), keys)
+	arcRatio := hitRatio(arc.New[uint64, struct{}](size), keys)
+	t.Logf("hit ratio: LRU: %.3f; ARC: %.3f", lruRatio, arcRatio)
+	if arcRatio < lruRatio+0.05 {
+		t.Fatalf("ARC isn't scan resistant: hit ratio: LRU: %.3f; ARC: %.3f", lruRatio, arcRatio)
+	}
+}
+
+var workloads = []struct {
+	name string
+	new  func() Generator
+}{
+	{"Uniform", func() Generator { return Uniform(1, 1<<14) }},
+	{"Zipf", func() Generator { return Zipf(1, 1<<14, 0.99) }},
+	{"Loop", func() Generator { return Loop(1 << 11) }},
+	{"HotSet", func() Generator { return HotSet(1, 1<<14, 1<<9, 1<<14) }},
+	{"ScanHot", func() Generator { return ScanHot(1, 1<<11, 0.5) }},
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	for _, w := range workloads {
+		b.Run(w.name, func(b *testing.B) {
+			g := w.new()
+			for i := 0; i < b.N; i++ {
+				g.Next()
+			}
+		})
+	}
+}
+
+// BenchmarkPolicy reports the speed and hit ratio of caches with 1024 items under each workload.
+func BenchmarkPolicy(b *testing.B) {
+	policies := []struct {
+		name string
+		new  func(size int) arc.Policy[uint64, struct{}]
+	}{
+		{"ARC", func(size int) arc.Policy[uint64, struct{}] { return arc.New[uint64, struct{}](size) }},
+		{"LRU", func(size int) arc.Policy[uint64, struct{}] { return policy.NewLRU[uint64, struct{}](size) }},
+	}
+	for _, w := range workloads {
+		keys := Take(w.new(), 1<<16)
+		for _, p := range policies {
+			b.Run(fmt.Sprintf("%s/%s", w.name, p.name), func(b *testing.B) {
+				c := p.new(1024)
+				var hits int
+				for i := 0; i < b.N; i++ {
+					k := keys[i%len(keys)]
+					if _, ok := c.Get(k); ok {
+						hits++
+					} else {
+						c.Set(k, struct{}{})
+					}
+				}
+				b.ReportMetric(float64(hits)/float64(b.N), "hits/op")
+			})
+		}
+	}
+}